@@ -1,6 +1,11 @@
 package ofbx
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
 
 // An AnimationStack is collection of 1 to n AnimationLayers along with possibily some properties
 type AnimationStack struct {
@@ -69,16 +74,65 @@ func (as *AnimationStack) stringPrefix(prefix string) string {
 	return s + "\n"
 }
 
+// LayerBlendMode is how a layer combines with the layers below it
+type LayerBlendMode int
+
+// LayerBlendMode options
+const (
+	// BlendAdditive adds the layer's values to those below it
+	BlendAdditive LayerBlendMode = iota
+	// BlendOverride replaces the values below the layer
+	BlendOverride LayerBlendMode = iota
+	// BlendOverridePassthrough replaces the values below the layer like BlendOverride,
+	// but lets them through on channels the layer has no keys for instead of
+	// replacing them with the curve node's static values
+	BlendOverridePassthrough LayerBlendMode = iota
+)
+
+// RotationAccumulationMode is how a layer's rotations combine with those below it
+type RotationAccumulationMode int
+
+// RotationAccumulationMode options
+const (
+	// RotationByLayer combines whole rotations, as quaternions
+	RotationByLayer RotationAccumulationMode = iota
+	// RotationByChannel combines each euler angle separately
+	RotationByChannel RotationAccumulationMode = iota
+)
+
+// ScaleAccumulationMode is how an additive layer's scaling combines with that below it
+type ScaleAccumulationMode int
+
+// ScaleAccumulationMode options
+const (
+	ScaleMultiply ScaleAccumulationMode = iota
+	ScaleAdditive ScaleAccumulationMode = iota
+)
+
 // AnimationLayer is a collection of AnimationCurveNodes along with possibily some properties
 type AnimationLayer struct {
 	Object
 	CurveNodes []*AnimationCurveNode
+	// Weight is the layer's influence, from 0 to 100
+	Weight               float64
+	Mute                 bool
+	Solo                 bool
+	BlendMode            LayerBlendMode
+	RotationAccumulation RotationAccumulationMode
+	ScaleAccumulation    ScaleAccumulationMode
 }
 
 // NewAnimationLayer creates a new AnimationLayer with no curvenodes
 func NewAnimationLayer(scene *Scene, element *Element) *AnimationLayer {
 	o := *NewObject(scene, element)
-	return &AnimationLayer{o, nil}
+	al := &AnimationLayer{Object: o}
+	al.Weight = resolveNumberProperty(al, "Weight", 100)
+	al.Mute = resolveEnumProperty(al, "Mute", 0) != 0
+	al.Solo = resolveEnumProperty(al, "Solo", 0) != 0
+	al.BlendMode = LayerBlendMode(resolveEnumProperty(al, "BlendMode", int(BlendAdditive)))
+	al.RotationAccumulation = RotationAccumulationMode(resolveEnumProperty(al, "RotationAccumulationMode", int(RotationByLayer)))
+	al.ScaleAccumulation = ScaleAccumulationMode(resolveEnumProperty(al, "ScaleAccumulationMode", int(ScaleMultiply)))
+	return al
 }
 
 // Type returns the type of Animation_layer
@@ -96,6 +150,31 @@ func (as *AnimationLayer) getCurveNode(bone Obj, property string) *AnimationCurv
 	return nil
 }
 
+// EvaluateNode returns the local translation, rotation and scaling of node at time t
+// in this layer. Rotation is in euler degrees. Channels this layer does not animate
+// keep the node's static values.
+func (as *AnimationLayer) EvaluateNode(node Obj, t time.Duration) (translation, rotation, scaling floatgeom.Point3) {
+	return as.evaluateNode(node, t, getLocalTranslation(node), getLocalRotation(node), getLocalScaling(node))
+}
+
+// evaluateNode overrides the given transform with this layer's curves for node
+func (as *AnimationLayer) evaluateNode(node Obj, t time.Duration, translation, rotation, scaling floatgeom.Point3) (floatgeom.Point3, floatgeom.Point3, floatgeom.Point3) {
+	for _, cn := range as.CurveNodes {
+		if cn.Bone == nil || cn.Bone.ID() != node.ID() {
+			continue
+		}
+		switch cn.BoneLinkProp {
+		case BoneTranslate:
+			translation = cn.evaluate(translation, t)
+		case BoneRotate:
+			rotation = cn.evaluate(rotation, t)
+		case BoneScale:
+			scaling = cn.evaluate(scaling, t)
+		}
+	}
+	return translation, rotation, scaling
+}
+
 // String pretty formats the AnimationLayers Curve Nodes
 func (as *AnimationLayer) String() string {
 	return as.stringPrefix("")
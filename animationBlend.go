@@ -0,0 +1,186 @@
+package ofbx
+
+import (
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// blendState is a node's local transform part way through combining a stack's layers.
+// Rotations stay euler angles until a layer combines them as quaternions.
+type blendState struct {
+	translation, rotation, scaling floatgeom.Point3
+	order                          RotationOrder
+	quat                           Quat
+	useQuat                        bool
+}
+
+func (bs *blendState) rotationQuat() Quat {
+	if bs.useQuat {
+		return bs.quat
+	}
+	return QuatFromEuler(bs.rotation, bs.order)
+}
+
+func (bs *blendState) setRotationQuat(q Quat) {
+	bs.quat = q
+	bs.useQuat = true
+}
+
+func (bs *blendState) rotationMatrix() Matrix {
+	if bs.useQuat {
+		return bs.quat.Matrix()
+	}
+	return bs.order.rotationMatrix(bs.rotation)
+}
+
+// evaluateNode returns node's local transform at t with all of the stack's layers combined.
+// The first layer is the base layer, which replaces the node's static values in proportion
+// to its weight. Later layers blend onto the result by their BlendMode, Weight and
+// accumulation modes. Muted layers are skipped, and if any layer is soloed only soloed
+// layers are blended onto the base.
+func (as *AnimationStack) evaluateNode(node Obj, t time.Duration) Matrix {
+	bs := &blendState{
+		translation: getLocalTranslation(node),
+		rotation:    getLocalRotation(node),
+		scaling:     getLocalScaling(node),
+		order:       getRotationOrder(node),
+	}
+	for _, layer := range as.activeLayers() {
+		layer.blend(bs, node, t, layer == as.Layers[0])
+	}
+	return evalLocalTransform(node, bs.translation, bs.rotationMatrix(), bs.scaling)
+}
+
+// activeLayers returns the layers which are blended when evaluating the stack. Muted
+// layers are skipped, and if any layer after the base layer is soloed only soloed
+// layers are blended onto the base.
+func (as *AnimationStack) activeLayers() []*AnimationLayer {
+	solo := false
+	for i, layer := range as.Layers {
+		solo = solo || (i != 0 && layer.Solo && !layer.Mute)
+	}
+	out := make([]*AnimationLayer, 0, len(as.Layers))
+	for i, layer := range as.Layers {
+		if layer.Mute || (i != 0 && solo && !layer.Solo) {
+			continue
+		}
+		out = append(out, layer)
+	}
+	return out
+}
+
+// blend combines the properties this layer animates on node into bs
+func (as *AnimationLayer) blend(bs *blendState, node Obj, t time.Duration, base bool) {
+	w := as.Weight / 100
+	for _, cn := range as.CurveNodes {
+		if cn.Bone == nil || cn.Bone.ID() != node.ID() || !cn.hasKeys() {
+			continue
+		}
+		var v floatgeom.Point3
+		switch cn.BoneLinkProp {
+		case BoneTranslate:
+			v = cn.evaluate(bs.translation, t)
+		case BoneRotate:
+			v = cn.evaluate(bs.rotation, t)
+		case BoneScale:
+			v = cn.evaluate(bs.scaling, t)
+		default:
+			continue
+		}
+		if !base && as.BlendMode != BlendOverridePassthrough {
+			// Channels without curves take the curve node's own values on later layers,
+			// unless the layer passes the values below it through
+			values := cn.Evaluate(t)
+			for c := 0; c < len(values) && c < 3; c++ {
+				if !cn.keyed(c) {
+					v[c] = float64(values[c])
+				}
+			}
+		}
+
+		additive := !base && as.BlendMode == BlendAdditive
+		switch cn.BoneLinkProp {
+		case BoneTranslate:
+			if additive {
+				bs.translation = bs.translation.Add(v.MulConst(w))
+			} else {
+				bs.translation = lerpPoint3(bs.translation, v, w)
+			}
+		case BoneRotate:
+			as.blendRotation(bs, v, w, base, additive)
+		case BoneScale:
+			switch {
+			case !additive:
+				bs.scaling = lerpPoint3(bs.scaling, v, w)
+			case as.ScaleAccumulation == ScaleAdditive:
+				bs.scaling = bs.scaling.Add(v.Sub(floatgeom.Point3{1, 1, 1}).MulConst(w))
+			default:
+				for c := 0; c < 3; c++ {
+					bs.scaling[c] *= 1 + (v[c]-1)*w
+				}
+			}
+		}
+	}
+}
+
+// blendRotation combines a rotation into bs. The base layer always blends by channel,
+// so a fully weighted base layer gives exactly its euler angles.
+func (as *AnimationLayer) blendRotation(bs *blendState, euler floatgeom.Point3, w float64, base, additive bool) {
+	if (base || as.RotationAccumulation == RotationByChannel) && !bs.useQuat {
+		if additive {
+			bs.rotation = bs.rotation.Add(euler.MulConst(w))
+		} else {
+			bs.rotation = lerpPoint3(bs.rotation, euler, w)
+		}
+		return
+	}
+	prev := bs.rotationQuat()
+	q := QuatFromEuler(euler, bs.order)
+	if additive {
+		bs.setRotationQuat(prev.Mul(IdentityQuat().Slerp(q, w)))
+	} else {
+		bs.setRotationQuat(prev.Slerp(q, w))
+	}
+}
+
+func lerpPoint3(a, b floatgeom.Point3, w float64) floatgeom.Point3 {
+	return a.Add(b.Sub(a).MulConst(w))
+}
+
+// evaluateProperty returns the single valued property prop of owner at t with the stack's
+// layers combined as in evaluateNode, starting from the static value
+func (as *AnimationStack) evaluateProperty(owner Obj, prop string, value float64, t time.Duration) float64 {
+	return as.evaluateChannels(owner, prop, []float64{value}, t)[0]
+}
+
+// evaluateChannels returns the channels of property prop of owner at t with the stack's
+// layers combined as in evaluateNode, starting from the static values
+func (as *AnimationStack) evaluateChannels(owner Obj, prop string, values []float64, t time.Duration) []float64 {
+	out := make([]float64, len(values))
+	copy(out, values)
+	for _, layer := range as.activeLayers() {
+		base := layer == as.Layers[0]
+		w := layer.Weight / 100
+		// The base layer and passthrough layers leave the channels they don't key alone
+		passthrough := base || layer.BlendMode == BlendOverridePassthrough
+		for _, cn := range layer.CurveNodes {
+			if cn.Bone == nil || cn.Bone.ID() != owner.ID() || cn.BoneLinkProp != prop || !cn.hasKeys() {
+				continue
+			}
+			keyed := cn.Evaluate(t)
+			for c := 0; c < len(out) && c < len(keyed); c++ {
+				if passthrough && !cn.keyed(c) {
+					continue
+				}
+				v := float64(keyed[c])
+				if !base && layer.BlendMode == BlendAdditive {
+					out[c] += v * w
+				} else {
+					out[c] += (v - out[c]) * w
+				}
+			}
+		}
+	}
+	return out
+}
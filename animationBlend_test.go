@@ -0,0 +1,105 @@
+package ofbx
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnimationLayerBlend(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	stack := scene.AnimationStacks[0]
+	base := stack.Layers[0]
+	require.Equal(t, 100.0, base.Weight)
+	require.Equal(t, BlendAdditive, base.BlendMode)
+	require.Equal(t, RotationByLayer, base.RotationAccumulation)
+	require.Equal(t, ScaleMultiply, base.ScaleAccumulation)
+	require.False(t, base.Mute)
+
+	bone := scene.RootNode.FindByName("Bone")
+	constant := func(v float32) *AnimationCurve {
+		return &AnimationCurve{Times: []time.Duration{0}, Values: []float32{v}}
+	}
+	layer := func(prop string, weight float64, mode LayerBlendMode, x, y, z float32) *AnimationLayer {
+		cn := &AnimationCurveNode{Bone: bone, BoneLinkProp: prop, Curves: make([]Curve, 3)}
+		for i, v := range []float32{x, y, z} {
+			cn.Curves[i].Curve = constant(v)
+		}
+		return &AnimationLayer{CurveNodes: []*AnimationCurveNode{cn}, Weight: weight, BlendMode: mode}
+	}
+	local := func(layers ...*AnimationLayer) Matrix {
+		s := &AnimationStack{Layers: append([]*AnimationLayer{base}, layers...)}
+		return bone.LocalTransformAt(s, time.Second)
+	}
+
+	require.InDelta(t, 2, local().At(1, 3), 1e-9)
+	require.InDelta(t, 4, local(layer(BoneTranslate, 50, BlendAdditive, 0, 4, 0)).At(1, 3), 1e-9)
+	require.InDelta(t, 3, local(layer(BoneTranslate, 50, BlendOverride, 0, 4, 0)).At(1, 3), 1e-9)
+
+	muted := layer(BoneTranslate, 100, BlendAdditive, 0, 4, 0)
+	muted.Mute = true
+	require.InDelta(t, 2, local(muted).At(1, 3), 1e-9)
+
+	solo := layer(BoneTranslate, 100, BlendAdditive, 0, 1, 0)
+	solo.Solo = true
+	require.InDelta(t, 3, local(layer(BoneTranslate, 100, BlendAdditive, 0, 4, 0), solo).At(1, 3), 1e-9)
+
+	// Additive rotations about the same axis sum either way they accumulate
+	angle := 100.5 * math.Pi / 180
+	byLayer := layer(BoneRotate, 100, BlendAdditive, 0, 0, 10)
+	byChannel := layer(BoneRotate, 100, BlendAdditive, 0, 0, 10)
+	byChannel.RotationAccumulation = RotationByChannel
+	for _, l := range []*AnimationLayer{byLayer, byChannel} {
+		m := local(l)
+		require.InDelta(t, math.Cos(angle), m.At(0, 0), 1e-9)
+		require.InDelta(t, math.Sin(angle), m.At(1, 0), 1e-9)
+	}
+
+	// Half of an override towards a quarter turn about x, from a rotation about z
+	override := layer(BoneRotate, 50, BlendOverride, 90, 0, 0)
+	_, r, _ := local(override).Decompose()
+	expected := QuatFromEuler(floatgeom.Point3{0, 0, 90.5}, EulerXYZ).Slerp(QuatFromEuler(floatgeom.Point3{90, 0, 0}, EulerXYZ), 0.5)
+	require.InDelta(t, 1, math.Abs(r.Dot(expected)), 1e-9)
+
+	// Passthrough layers let the values below them through on channels they don't key,
+	// where override layers take the curve node's static values
+	partial := func(mode LayerBlendMode) *AnimationLayer {
+		l := layer(BoneTranslate, 100, mode, 0, 4, 0)
+		l.CurveNodes[0].Curves[0].Curve = nil
+		l.CurveNodes[0].defaults = []float32{7, 0, 0}
+		return l
+	}
+	m := local(partial(BlendOverride))
+	require.InDelta(t, 7, m.At(0, 3), 1e-9)
+	require.InDelta(t, 4, m.At(1, 3), 1e-9)
+	m = local(partial(BlendOverridePassthrough))
+	require.InDelta(t, 0, m.At(0, 3), 1e-9)
+	require.InDelta(t, 4, m.At(1, 3), 1e-9)
+
+	channels := func(mode LayerBlendMode) []float64 {
+		cn := &AnimationCurveNode{Bone: bone, BoneLinkProp: "Color", Curves: make([]Curve, 2)}
+		cn.Curves[1].Curve = constant(3)
+		l := &AnimationLayer{CurveNodes: []*AnimationCurveNode{cn}, Weight: 50, BlendMode: mode}
+		s := &AnimationStack{Layers: []*AnimationLayer{{Weight: 100}, l}}
+		return s.evaluateChannels(bone, "Color", []float64{1, 1}, time.Second)
+	}
+	require.Equal(t, []float64{0.5, 2}, channels(BlendOverride))
+	require.Equal(t, []float64{1, 2}, channels(BlendOverridePassthrough))
+
+	// Property channels skip muted layers like nodes do
+	tint := &AnimationCurveNode{Bone: bone, BoneLinkProp: "Color", Curves: []Curve{{Curve: constant(3)}}}
+	mutedTint := &AnimationLayer{CurveNodes: []*AnimationCurveNode{tint}, Weight: 100, Mute: true}
+	mutedStack := &AnimationStack{Layers: []*AnimationLayer{{Weight: 100}, mutedTint}}
+	require.Equal(t, []float64{1}, mutedStack.evaluateChannels(bone, "Color", []float64{1}, time.Second))
+
+	scaled := layer(BoneScale, 50, BlendAdditive, 2, 1, 1)
+	_, _, s := local(scaled).Decompose()
+	require.InDelta(t, 1.5, s.X(), 1e-9)
+	scaled.ScaleAccumulation = ScaleAdditive
+	scaled.Weight = 100
+	_, _, s = local(scaled, layer(BoneScale, 100, BlendAdditive, 3, 1, 1)).Decompose()
+	require.InDelta(t, 6, s.X(), 1e-9)
+}
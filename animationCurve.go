@@ -2,6 +2,7 @@ package ofbx
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -34,6 +35,8 @@ type AnimationCurve struct {
 	AttrFlags    []int64
 	AttrData     []float32
 	AttrRefCount []int64
+	// Keys holds the decoded attributes of each key, if the curve has any
+	Keys []Key
 }
 
 // NewAnimationCurve creates a new stub AnimationCurve
@@ -65,10 +68,14 @@ func (ac *AnimationCurve) stringPrefix(prefix string) string {
 // AnimationCurveNode is a mapping of Curve to a property
 type AnimationCurveNode struct {
 	Object
-	Curves       [3]Curve
+	// Curves holds a curve, or none, for each of the node's channels
+	Curves       []Curve
 	Bone         Obj
 	BoneLinkProp string
 	mode         CurveMode
+	// channels are the names of the node's properties, e.g. d|X, and defaults their static values
+	channels []string
+	defaults []float32
 }
 
 // Curve is a connection Linkage for an AnimationCurve
@@ -89,6 +96,25 @@ func NewAnimationCurveNode(s *Scene, e *Element) *AnimationCurveNode {
 	acn := AnimationCurveNode{}
 	obj := *NewObject(s, e)
 	acn.Object = obj
+	for _, p := range findChildren(e, "Properties70") {
+		if p.ID.String() != "Properties70" {
+			continue
+		}
+		for _, prop := range p.Children {
+			name := prop.getProperty(0)
+			if name == nil || !strings.HasPrefix(name.value.String(), "d|") {
+				continue
+			}
+			acn.channels = append(acn.channels, name.value.String())
+			var def float32
+			if v := prop.getProperty(4); v != nil {
+				def = float32(v.number())
+			}
+			acn.defaults = append(acn.defaults, def)
+		}
+		break
+	}
+	acn.Curves = make([]Curve, len(acn.channels))
 	return &acn
 }
 
@@ -97,38 +123,97 @@ func (acn *AnimationCurveNode) Type() Type {
 	return ANIMATION_CURVE_NODE
 }
 
-func (acn *AnimationCurveNode) getNodeLocalTransform(t float64) floatgeom.Point3 {
-	fbxTime := fbxTimetoStdTime(secondsToFbxTime(t))
-
-	getCoord := func(curve *Curve, fbxTime time.Duration) float32 {
-		if curve.Curve == nil {
-			return 0.0
+// addCurve connects a curve to the channel named by property, or to the first free channel.
+// Curves of nodes without a free channel are added as a new channel.
+func (acn *AnimationCurveNode) addCurve(curve *AnimationCurve, con *Connection) {
+	for len(acn.Curves) < len(acn.channels) {
+		acn.Curves = append(acn.Curves, Curve{})
+	}
+	for i, name := range acn.channels {
+		if name == con.property && acn.Curves[i].Curve == nil {
+			acn.Curves[i] = Curve{curve, con}
+			return
 		}
+	}
+	for i := range acn.Curves {
+		if acn.Curves[i].Curve == nil {
+			acn.Curves[i] = Curve{curve, con}
+			return
+		}
+	}
+	acn.Curves = append(acn.Curves, Curve{curve, con})
+}
 
-		times := curve.Curve.Times
-		values := curve.Curve.Values
-		count := len(times)
+// Channels returns how many values the node animates, e.g. three for
+// translation or one for a camera's focal length
+func (acn *AnimationCurveNode) Channels() int {
+	if len(acn.Curves) > len(acn.channels) {
+		return len(acn.Curves)
+	}
+	return len(acn.channels)
+}
 
-		if fbxTime < times[0] {
-			fbxTime = times[0]
+// keyed returns whether the i'th channel has a curve with keys
+func (acn *AnimationCurveNode) keyed(i int) bool {
+	if i >= len(acn.Curves) {
+		return false
+	}
+	curve := acn.Curves[i].Curve
+	return curve != nil && len(curve.Times) != 0
+}
+
+// Evaluate returns the value of each of the node's channels at t.
+// Channels without keys keep the node's static value.
+func (acn *AnimationCurveNode) Evaluate(t time.Duration) []float32 {
+	out := make([]float32, acn.Channels())
+	for i := range out {
+		if acn.keyed(i) {
+			out[i] = acn.Curves[i].Curve.Evaluate(t)
+		} else if i < len(acn.defaults) {
+			out[i] = acn.defaults[i]
 		}
-		if fbxTime > times[count-1] {
-			fbxTime = times[count-1]
+	}
+	return out
+}
+
+// hasKeys returns whether any of the node's curves has keys
+func (acn *AnimationCurveNode) hasKeys() bool {
+	for i := range acn.Curves {
+		if acn.keyed(i) {
+			return true
 		}
-		for i := 1; i < count; i++ {
-			if times[i] >= fbxTime {
-				t := float32(float64(fbxTime-times[i-1]) / float64(times[i]-times[i-1]))
-				return values[i-1]*(1-t) + values[i]*t
-			}
+	}
+	return false
+}
+
+// evaluate replaces the components of base which have curves with the curves' values at t
+func (acn *AnimationCurveNode) evaluate(base floatgeom.Point3, t time.Duration) floatgeom.Point3 {
+	for i := range base {
+		if acn.keyed(i) {
+			base[i] = float64(acn.Curves[i].Curve.Evaluate(t))
 		}
-		return values[0]
 	}
+	return base
+}
 
-	return floatgeom.Point3{
-		float64(getCoord(&acn.Curves[0], fbxTime)),
-		float64(getCoord(&acn.Curves[1], fbxTime)),
-		float64(getCoord(&acn.Curves[2], fbxTime)),
+// Evaluate interpolates the curve's keys at t, as each key's interpolation mode says,
+// clamping to the first and last keys. A curve without keys evaluates to 0.
+func (ac *AnimationCurve) Evaluate(t time.Duration) float32 {
+	times := ac.Times
+	values := ac.Values
+	count := len(times)
+	if count == 0 || len(values) < count {
+		return 0
+	}
+
+	if t <= times[0] {
+		return values[0]
+	}
+	if t >= times[count-1] {
+		return values[count-1]
 	}
+	i := sort.Search(count, func(i int) bool { return times[i] > t })
+	return ac.interpolate(i-1, t)
 }
 
 // String pretty formats the AnimationCurveNode
@@ -147,7 +232,7 @@ func (acn *AnimationCurveNode) stringPrefix(prefix string) string {
 
 	// NOTE: an animation curve node which specify the focal length property has
 	// exactly one curve (FocalLength), rather than three (X,Y,Z).
-	if strings.HasPrefix(acn.Object.name, "FocalLength") {
+	if strings.HasPrefix(acn.Object.name, "FocalLength") && len(acn.Curves) != 0 {
 		s += "\n" + prefix + "\t"
 		s += " FocalLength: "
 		s += acn.Curves[0].String()
@@ -163,6 +248,8 @@ func (acn *AnimationCurveNode) stringPrefix(prefix string) string {
 			s += " Y: "
 		case 2:
 			s += " Z: "
+		default:
+			s += fmt.Sprintf(" %d: ", i)
 		}
 		s += curve.String()
 	}
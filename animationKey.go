@@ -0,0 +1,351 @@
+package ofbx
+
+import (
+	"math"
+	"time"
+)
+
+// KeyInterpolation is how a curve moves from a key to the next one
+type KeyInterpolation int
+
+// KeyInterpolation options
+const (
+	// InterpolationConstant holds the key's value until the next key
+	InterpolationConstant KeyInterpolation = iota
+	// InterpolationLinear moves in a straight line to the next key
+	InterpolationLinear KeyInterpolation = iota
+	// InterpolationCubic follows a bezier curve shaped by the keys' slopes and weights
+	InterpolationCubic KeyInterpolation = iota
+)
+
+// TangentMode is how a cubic key's slopes were authored
+type TangentMode int
+
+// TangentMode options
+const (
+	TangentAuto  TangentMode = iota
+	TangentTCB   TangentMode = iota
+	TangentUser  TangentMode = iota
+	TangentBreak TangentMode = iota
+)
+
+// Key flag bits, as defined by FbxAnimCurveDef
+const (
+	keyInterpolationConstant = 0x2
+	keyInterpolationLinear   = 0x4
+	keyInterpolationCubic    = 0x8
+	keyTangentAuto           = 0x100
+	keyTangentTCB            = 0x200
+	keyTangentUser           = 0x400
+	keyTangentBreak          = 0x800
+	keyConstantNext          = 0x100
+	keyWeightedRight         = 0x1000000
+	keyWeightedNextLeft      = 0x2000000
+	keyVelocityRight         = 0x10000000
+	keyVelocityNextLeft      = 0x20000000
+
+	// keyKnownFlags are the bits Key holds, other bits are kept as they were read
+	keyKnownFlags = keyInterpolationConstant | keyInterpolationLinear | keyInterpolationCubic |
+		keyTangentAuto | keyTangentTCB | keyTangentUser | keyTangentBreak |
+		keyWeightedRight | keyWeightedNextLeft | keyVelocityRight | keyVelocityNextLeft
+)
+
+// keyWeightDivider converts the fixed point weights and velocities FBX stores to floats
+const keyWeightDivider = 9999
+
+// defaultKeyWeight is the weight of an unweighted tangent, which makes a bezier segment a hermite one
+const defaultKeyWeight = 1.0 / 3.0
+
+// Key holds the attributes of one key of an AnimationCurve. The Right values shape the
+// curve leaving this key, the NextLeft values shape it arriving at the next key.
+// Slopes are in value units per second.
+type Key struct {
+	Interpolation KeyInterpolation
+	TangentMode   TangentMode
+	// ConstantNext makes a constant key hold the next key's value instead of its own
+	ConstantNext bool
+
+	RightSlope, NextLeftSlope       float32
+	RightWeight, NextLeftWeight     float32
+	RightVelocity, NextLeftVelocity float32
+
+	// Tension, Continuity and Bias are set for TCB keys, whose slopes are derived from them
+	Tension, Continuity, Bias float32
+
+	// flags holds the bits of the key's attribute which Key has no fields for
+	flags int64
+}
+
+// decodeKeys expands the curve's shared key attributes into one Key per key value.
+// Each attribute is used by as many keys as its reference count says.
+func (ac *AnimationCurve) decodeKeys() {
+	if len(ac.AttrFlags) == 0 {
+		return
+	}
+	ac.Keys = make([]Key, len(ac.Times))
+	attr := 0
+	used := int64(0)
+	for i := range ac.Keys {
+		if attr < len(ac.AttrRefCount) && used >= ac.AttrRefCount[attr] && attr+1 < len(ac.AttrFlags) {
+			attr++
+			used = 0
+		}
+		if attr >= len(ac.AttrFlags) {
+			attr = len(ac.AttrFlags) - 1
+		}
+		var data [4]float32
+		if len(ac.AttrData) >= attr*4+4 {
+			copy(data[:], ac.AttrData[attr*4:])
+		}
+		ac.Keys[i] = newKey(ac.AttrFlags[attr], data)
+		used++
+	}
+	ac.computeTCBSlopes()
+}
+
+func newKey(flags int64, data [4]float32) Key {
+	k := Key{
+		Interpolation:  InterpolationLinear,
+		RightWeight:    defaultKeyWeight,
+		NextLeftWeight: defaultKeyWeight,
+		flags:          flags &^ keyKnownFlags,
+	}
+	switch {
+	case flags&keyInterpolationConstant != 0:
+		k.Interpolation = InterpolationConstant
+		k.ConstantNext = flags&keyConstantNext != 0
+		return k
+	case flags&keyInterpolationCubic != 0:
+		k.Interpolation = InterpolationCubic
+	}
+	switch {
+	case flags&keyTangentBreak != 0:
+		k.TangentMode = TangentBreak
+	case flags&keyTangentUser != 0:
+		k.TangentMode = TangentUser
+	case flags&keyTangentTCB != 0:
+		k.TangentMode = TangentTCB
+	}
+
+	if k.TangentMode == TangentTCB {
+		k.Tension, k.Continuity, k.Bias = data[0], data[1], data[2]
+	} else {
+		k.RightSlope, k.NextLeftSlope = data[0], data[1]
+	}
+	right, nextLeft := unpackKeyPair(data[2])
+	if flags&keyWeightedRight != 0 {
+		k.RightWeight = right
+	}
+	if flags&keyWeightedNextLeft != 0 {
+		k.NextLeftWeight = nextLeft
+	}
+	right, nextLeft = unpackKeyPair(data[3])
+	if flags&keyVelocityRight != 0 {
+		k.RightVelocity = right
+	}
+	if flags&keyVelocityNextLeft != 0 {
+		k.NextLeftVelocity = nextLeft
+	}
+	return k
+}
+
+// encode returns the key's attribute flags and data, as newKey reads them
+func (k Key) encode() (int64, [4]float32) {
+	flags := k.flags
+	var data [4]float32
+	switch k.Interpolation {
+	case InterpolationConstant:
+		flags |= keyInterpolationConstant
+		if k.ConstantNext {
+			flags |= keyConstantNext
+		}
+		data[2] = packKeyPair(defaultKeyWeight, defaultKeyWeight)
+		return flags, data
+	case InterpolationCubic:
+		flags |= keyInterpolationCubic
+	default:
+		flags |= keyInterpolationLinear
+	}
+	switch k.TangentMode {
+	case TangentTCB:
+		flags |= keyTangentTCB
+		data[0], data[1], data[2] = k.Tension, k.Continuity, k.Bias
+	case TangentUser:
+		flags |= keyTangentUser
+	case TangentBreak:
+		flags |= keyTangentBreak | keyTangentUser
+	default:
+		flags |= keyTangentAuto
+	}
+	if k.TangentMode != TangentTCB {
+		data[0], data[1] = k.RightSlope, k.NextLeftSlope
+		if k.RightWeight != defaultKeyWeight {
+			flags |= keyWeightedRight
+		}
+		if k.NextLeftWeight != defaultKeyWeight {
+			flags |= keyWeightedNextLeft
+		}
+		data[2] = packKeyPair(k.RightWeight, k.NextLeftWeight)
+	}
+	if k.RightVelocity != 0 {
+		flags |= keyVelocityRight
+	}
+	if k.NextLeftVelocity != 0 {
+		flags |= keyVelocityNextLeft
+	}
+	data[3] = packKeyPair(k.RightVelocity, k.NextLeftVelocity)
+	return flags, data
+}
+
+// encodeKeys rebuilds the curve's shared key attributes from its Keys, sharing
+// each attribute between runs of keys which have the same one
+func (ac *AnimationCurve) encodeKeys() {
+	ac.AttrFlags, ac.AttrData, ac.AttrRefCount = nil, nil, nil
+	var last [4]float32
+	for i, k := range ac.Keys {
+		flags, data := k.encode()
+		if i != 0 && flags == ac.AttrFlags[len(ac.AttrFlags)-1] && data == last {
+			ac.AttrRefCount[len(ac.AttrRefCount)-1]++
+			continue
+		}
+		ac.AttrFlags = append(ac.AttrFlags, flags)
+		ac.AttrData = append(ac.AttrData, data[:]...)
+		ac.AttrRefCount = append(ac.AttrRefCount, 1)
+		last = data
+	}
+}
+
+// packKeyPair stores two values as 16 bit fixed point in the bits of a float, as binary files do
+func packKeyPair(low, high float32) float32 {
+	l := uint16(int16(math.Round(float64(low * keyWeightDivider))))
+	h := uint16(int16(math.Round(float64(high * keyWeightDivider))))
+	return math.Float32frombits(uint32(h)<<16 | uint32(l))
+}
+
+// unpackKeyPair splits a float slot holding two 16 bit fixed point values in its bits
+func unpackKeyPair(f float32) (low, high float32) {
+	bits := math.Float32bits(f)
+	return float32(int16(bits)) / keyWeightDivider, float32(int16(bits>>16)) / keyWeightDivider
+}
+
+// parseKeyAttrData reads a curve's KeyAttrDataFloat. Weights and velocities are packed
+// pairs, which binary files store as the bits of a float and ASCII files write as the
+// packed integer. Those integers are turned back into the same bits here, as a float32
+// can't hold them exactly.
+func parseKeyAttrData(property *Property, flags []int64) ([]float32, error) {
+	if property.Type == ArrayFLOAT {
+		return property.getValuesF32()
+	}
+	f64s, err := parseArrayRawFloat64(property)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float32, len(f64s))
+	for i, f := range f64s {
+		out[i] = float32(f)
+		if keyAttrPacked(i, flags) {
+			out[i] = math.Float32frombits(uint32(int32(int64(f))))
+		}
+	}
+	return out, nil
+}
+
+// keyAttrPacked reports whether the i'th KeyAttrDataFloat value is a packed pair.
+// TCB keys keep their bias, a plain float, where other keys keep their weights.
+func keyAttrPacked(i int, flags []int64) bool {
+	attr := i / 4
+	return i%4 == 3 || (i%4 == 2 && (attr >= len(flags) || flags[attr]&keyTangentTCB == 0))
+}
+
+// computeTCBSlopes derives the slopes of TCB keys from their neighbors, as Kochanek-Bartels splines
+func (ac *AnimationCurve) computeTCBSlopes() {
+	for i, k := range ac.Keys {
+		if k.TangentMode != TangentTCB {
+			continue
+		}
+		prev, next := ac.segmentSlope(i-1), ac.segmentSlope(i)
+		if i == 0 {
+			prev = next
+		}
+		if i == len(ac.Keys)-1 {
+			next = prev
+		}
+		t, c, b := k.Tension, k.Continuity, k.Bias
+		ac.Keys[i].RightSlope = (1-t)*(1+c)*(1+b)/2*prev + (1-t)*(1-c)*(1-b)/2*next
+		if i > 0 {
+			ac.Keys[i-1].NextLeftSlope = (1-t)*(1-c)*(1+b)/2*prev + (1-t)*(1+c)*(1-b)/2*next
+		}
+	}
+}
+
+// segmentSlope returns the average slope between key i and the next key, or 0 if there is no such segment
+func (ac *AnimationCurve) segmentSlope(i int) float32 {
+	if i < 0 || i+1 >= len(ac.Times) {
+		return 0
+	}
+	dt := (ac.Times[i+1] - ac.Times[i]).Seconds()
+	if dt == 0 {
+		return 0
+	}
+	return float32(float64(ac.Values[i+1]-ac.Values[i]) / dt)
+}
+
+// interpolate evaluates the segment between key i and the next key at t
+func (ac *AnimationCurve) interpolate(i int, t time.Duration) float32 {
+	t0, t1 := ac.Times[i], ac.Times[i+1]
+	v0, v1 := ac.Values[i], ac.Values[i+1]
+	s := float64(t-t0) / float64(t1-t0)
+	if len(ac.Keys) != len(ac.Times) {
+		return v0 + (v1-v0)*float32(s)
+	}
+	k := ac.Keys[i]
+	switch k.Interpolation {
+	case InterpolationConstant:
+		if k.ConstantNext {
+			return v1
+		}
+		return v0
+	case InterpolationCubic:
+		return cubicBezier(k, s, (t1 - t0).Seconds(), v0, v1)
+	}
+	return v0 + (v1-v0)*float32(s)
+}
+
+// cubicBezier evaluates a cubic segment at s, the fraction of the segment's duration
+// dt which has passed. Each tangent's control point sits its weight along the segment
+// in time, following its slope, so the curve is solved for the time s first.
+func cubicBezier(k Key, s, dt float64, v0, v1 float32) float32 {
+	w0 := clampWeight(float64(k.RightWeight))
+	w1 := clampWeight(float64(k.NextLeftWeight))
+	p0 := float64(v0)
+	p1 := p0 + float64(k.RightSlope)*w0*dt
+	p3 := float64(v1)
+	p2 := p3 - float64(k.NextLeftSlope)*w1*dt
+	x1, x2 := w0, 1-w1
+
+	// x is monotonic in u as both control points lie within the segment
+	lo, hi := 0.0, 1.0
+	u := s
+	for iter := 0; iter < 32; iter++ {
+		x := bezier(0, x1, x2, 1, u)
+		if math.Abs(x-s) < 1e-7 {
+			break
+		}
+		if x < s {
+			lo = u
+		} else {
+			hi = u
+		}
+		u = (lo + hi) / 2
+	}
+	return float32(bezier(p0, p1, p2, p3, u))
+}
+
+func bezier(p0, p1, p2, p3, u float64) float64 {
+	inv := 1 - u
+	return inv*inv*inv*p0 + 3*inv*inv*u*p1 + 3*inv*u*u*p2 + u*u*u*p3
+}
+
+func clampWeight(w float64) float64 {
+	return math.Max(0, math.Min(1, w))
+}
@@ -0,0 +1,156 @@
+package ofbx
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFbxTime(t *testing.T) {
+	require.Equal(t, int64(46186158000), secondsToFbxTime(1))
+	require.Equal(t, 0.5, fbxTimeToSeconds(secondsToFbxTime(0.5)))
+	require.Equal(t, 1500*time.Millisecond, fbxTimetoStdTime(secondsToFbxTime(1.5)))
+}
+
+func TestAnimationEvaluate(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	layer := scene.AnimationStacks[0].Layers[0]
+	node := layer.CurveNodes[0]
+	curve := node.Curves[2].Curve
+
+	require.Equal(t, float32(0), curve.Evaluate(-time.Second))
+	require.Equal(t, float32(22.5), curve.Evaluate(250*time.Millisecond))
+	require.Equal(t, float32(67.75), curve.Evaluate(750*time.Millisecond))
+	require.Equal(t, float32(90.5), curve.Evaluate(2*time.Second))
+	require.Equal(t, float32(0), (&AnimationCurve{}).Evaluate(time.Second))
+
+	require.Equal(t, 3, node.Channels())
+	require.Equal(t, []float32{0, 0, 45}, node.Evaluate(500*time.Millisecond))
+
+	bone := scene.RootNode.FindByName("Bone")
+	translation, rotation, scaling := layer.EvaluateNode(bone, time.Second)
+	require.Equal(t, getLocalTranslation(bone), translation)
+	require.Equal(t, floatgeom.Point3{0, 0, 90.5}, rotation)
+	require.Equal(t, floatgeom.Point3{1, 1, 1}, scaling)
+
+	// Nodes without curves in the layer keep their static transform
+	translation, rotation, _ = layer.EvaluateNode(scene.Meshes[0], time.Second)
+	require.Equal(t, floatgeom.Point3{1.5, -2, 0.3}, translation)
+	require.Equal(t, getLocalRotation(scene.Meshes[0]), rotation)
+}
+
+func TestAnimationCurveNodeChannels(t *testing.T) {
+	node := &AnimationCurveNode{channels: []string{"d|X", "d|Y", "d|Z"}, defaults: []float32{1, 2, 3}}
+	z := &AnimationCurve{Times: []time.Duration{0}, Values: []float32{7}}
+	node.addCurve(z, &Connection{property: "d|Z"})
+	require.Equal(t, z, node.Curves[2].Curve)
+	require.Equal(t, []float32{1, 2, 7}, node.Evaluate(0))
+
+	focal := &AnimationCurveNode{channels: []string{"d|FocalLength"}, defaults: []float32{35}}
+	require.Equal(t, 1, focal.Channels())
+	require.Equal(t, []float32{35}, focal.Evaluate(0))
+
+	// Nodes have as many channels as d| properties, typed however they are stored
+	root, err := tokenizeText(textCursor(`AnimationCurveNode: 502, "AnimCurveNode::Color", "" {
+	Properties70:  {
+		P: "d|R", "Number", "", "A",0.25
+		P: "d|G", "Number", "", "A",0.5
+		P: "d|B", "Number", "", "A",0.75
+		P: "d|A", "Number", "", "A",1
+	}
+}`))
+	require.Nil(t, err)
+	element := root.Children[0]
+	element.Children[0].Children[3].Properties[4] = NewIntegerProperty(1)
+	color := NewAnimationCurveNode(nil, element)
+	require.Len(t, color.Curves, 4)
+	require.Equal(t, []float32{0.25, 0.5, 0.75, 1}, color.Evaluate(0))
+	a := &AnimationCurve{Times: []time.Duration{0}, Values: []float32{0.5}}
+	color.addCurve(a, &Connection{property: "d|A"})
+	require.Equal(t, a, color.Curves[3].Curve)
+	require.Equal(t, []float32{0.25, 0.5, 0.75, 0.5}, color.Evaluate(0))
+}
+
+func TestKeyAttrData(t *testing.T) {
+	// ASCII files write packed pairs as integers, which float32s can't hold exactly
+	flags := []int64{keyInterpolationCubic, keyInterpolationCubic | keyTangentTCB}
+	for _, vals := range [][]string{
+		{"0", "0", "218434821", "-327675000", "0", "0", "0", "0"},
+		{"0", "0", "218434821", "-327675000", "0", "0", "0.25", "0"},
+	} {
+		prop, err := textArrayProperty(vals, 0)
+		require.Nil(t, err)
+		data, err := parseKeyAttrData(prop, flags)
+		require.Nil(t, err)
+		low, high := unpackKeyPair(data[2])
+		require.Equal(t, float32(3333)/keyWeightDivider, low)
+		require.Equal(t, float32(3333)/keyWeightDivider, high)
+		low, high = unpackKeyPair(data[3])
+		require.Equal(t, float32(5000)/keyWeightDivider, low)
+		require.Equal(t, float32(-5000)/keyWeightDivider, high)
+		// TCB keys keep their bias there
+		require.Equal(t, vals[6] == "0.25", data[6] == 0.25)
+	}
+}
+
+func TestAnimationKeys(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	curve := scene.AnimationStacks[0].Layers[0].CurveNodes[0].Curves[2].Curve
+	require.Len(t, curve.Keys, 3)
+	require.Equal(t, InterpolationCubic, curve.Keys[1].Interpolation)
+	require.Equal(t, TangentAuto, curve.Keys[1].TangentMode)
+	require.InDelta(t, 1.0/3, curve.Keys[1].RightWeight, 1e-6)
+
+	low, high := unpackKeyPair(math.Float32frombits(0x0D050D05))
+	require.Equal(t, float32(3333)/keyWeightDivider, low)
+	require.Equal(t, float32(3333)/keyWeightDivider, high)
+	// A negative high half makes the float a large integral negative number
+	low, high = unpackKeyPair(math.Float32frombits(0xEC781388))
+	require.Equal(t, float32(5000)/keyWeightDivider, low)
+	require.Equal(t, float32(-5000)/keyWeightDivider, high)
+
+	keyed := func(flags int64, data ...float32) *AnimationCurve {
+		c := &AnimationCurve{
+			Times:        []time.Duration{0, time.Second, 2 * time.Second},
+			Values:       []float32{0, 10, 0},
+			AttrFlags:    []int64{flags, keyInterpolationLinear},
+			AttrData:     append(data, 0, 0, 0, 0),
+			AttrRefCount: []int64{1, 2},
+		}
+		c.decodeKeys()
+		return c
+	}
+
+	constant := keyed(keyInterpolationConstant, 0, 0, 0, 0)
+	require.Equal(t, InterpolationLinear, constant.Keys[1].Interpolation)
+	require.Equal(t, float32(0), constant.Evaluate(900*time.Millisecond))
+	require.Equal(t, float32(5), constant.Evaluate(1500*time.Millisecond))
+	require.Equal(t, float32(10), keyed(keyInterpolationConstant|keyConstantNext, 0, 0, 0, 0).Evaluate(100*time.Millisecond))
+
+	// Zero slopes ease in and out as a smoothstep
+	flat := keyed(keyInterpolationCubic, 0, 0, 0, 0)
+	require.InDelta(t, 10*(3*0.25*0.25-2*0.25*0.25*0.25), flat.Evaluate(250*time.Millisecond), 1e-4)
+	require.InDelta(t, 5, flat.Evaluate(500*time.Millisecond), 1e-4)
+
+	// A hermite segment with both slopes at 10 is a straight line
+	straight := keyed(keyInterpolationCubic|keyTangentUser, 10, 10, 0, 0)
+	require.Equal(t, TangentUser, straight.Keys[0].TangentMode)
+	require.InDelta(t, 2.5, straight.Evaluate(250*time.Millisecond), 1e-4)
+
+	// Weights pull the curve towards each tangent
+	weighted := keyed(keyInterpolationCubic|keyWeightedRight|keyWeightedNextLeft, 0, 0, math.Float32frombits(0x0001270F), 0)
+	require.InDelta(t, 1, weighted.Keys[0].RightWeight, 1e-6)
+	require.InDelta(t, 1.0/keyWeightDivider, weighted.Keys[0].NextLeftWeight, 1e-6)
+	require.True(t, weighted.Evaluate(500*time.Millisecond) < flat.Evaluate(500*time.Millisecond))
+
+	// TCB keys with no tension, continuity or bias leave with the slope of their neighbors,
+	// here only the next key, which is linear and so arrives flat
+	tcb := keyed(keyInterpolationCubic|keyTangentTCB, 0, 0, 0, 0)
+	require.Equal(t, TangentTCB, tcb.Keys[0].TangentMode)
+	require.InDelta(t, 10, tcb.Keys[0].RightSlope, 1e-6)
+	require.Equal(t, float32(0), tcb.Keys[0].NextLeftSlope)
+	require.InDelta(t, 6.25, tcb.Evaluate(500*time.Millisecond), 1e-4)
+}
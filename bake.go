@@ -0,0 +1,173 @@
+package ofbx
+
+import (
+	"sort"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// DefaultBakeRate is the number of samples per second Bake takes when
+// neither its fps nor the scene's frame rate are set
+const DefaultBakeRate = 30
+
+// BakeOptions control how an AnimationStack is baked
+type BakeOptions struct {
+	// Start and End override the stack's time span when End is after Start
+	Start, End time.Duration
+	// IncludeStatic bakes every node in the scene, not just the animated ones
+	IncludeStatic bool
+}
+
+// BakedAnimation is an AnimationStack sampled at a fixed rate
+type BakedAnimation struct {
+	Name      string
+	FrameRate float64
+	// Times holds the time of each sample. They are FrameRate apart, except
+	// for the last one which is always at the end of the span.
+	Times  []time.Duration
+	Tracks []*Track
+}
+
+// Duration returns the time between the first and last samples
+func (ba *BakedAnimation) Duration() time.Duration {
+	if len(ba.Times) == 0 {
+		return 0
+	}
+	return ba.Times[len(ba.Times)-1] - ba.Times[0]
+}
+
+// Track is a node's local transform at each of a BakedAnimation's Times
+type Track struct {
+	Node         Obj
+	Translations []floatgeom.Point3
+	// Rotations are kept in the same hemisphere as their predecessor, so
+	// interpolating between neighbors takes the short way around
+	Rotations []Quat
+	Scales    []floatgeom.Point3
+}
+
+// Bake samples the local transform of every animated node fps times a second over
+// the stack's time span, with fps defaulting to the scene's frame rate. Pivots,
+// offsets and pre and post rotations are applied before splitting the transforms.
+func (as *AnimationStack) Bake(fps float64, opts BakeOptions) *BakedAnimation {
+	if fps <= 0 && as.scene != nil {
+		fps = float64(as.scene.FrameRate)
+	}
+	if fps <= 0 {
+		fps = DefaultBakeRate
+	}
+	start, end := opts.Start, opts.End
+	if end <= start {
+		start, end = as.TimeSpan()
+	}
+
+	baked := &BakedAnimation{Name: BaseName(as.Name()), FrameRate: fps}
+	for i := 0; ; i++ {
+		t := start + time.Duration(float64(i)/fps*float64(time.Second))
+		if t >= end {
+			break
+		}
+		baked.Times = append(baked.Times, t)
+	}
+	baked.Times = append(baked.Times, end)
+
+	for _, node := range as.bakedNodes(opts.IncludeStatic) {
+		track := &Track{
+			Node:         node,
+			Translations: make([]floatgeom.Point3, len(baked.Times)),
+			Rotations:    make([]Quat, len(baked.Times)),
+			Scales:       make([]floatgeom.Point3, len(baked.Times)),
+		}
+		for i, t := range baked.Times {
+			tr, r, s := node.LocalTransformAt(as, t).Decompose()
+			if i != 0 && track.Rotations[i-1].Dot(r) < 0 {
+				r = Quat{-r.X, -r.Y, -r.Z, -r.W}
+			}
+			track.Translations[i], track.Rotations[i], track.Scales[i] = tr, r, s
+		}
+		baked.Tracks = append(baked.Tracks, track)
+	}
+	return baked
+}
+
+// bakedNodes returns the nodes a bake has tracks for, ordered by id
+func (as *AnimationStack) bakedNodes(includeStatic bool) []Obj {
+	nodes := map[uint64]Obj{}
+	if includeStatic && as.scene != nil && as.scene.RootNode != nil {
+		as.scene.RootNode.Walk(func(o Obj) bool {
+			nodes[o.ID()] = o
+			return true
+		})
+	}
+	for _, layer := range as.Layers {
+		for _, cn := range layer.CurveNodes {
+			if cn.Bone == nil || !cn.Bone.IsNode() {
+				continue
+			}
+			switch cn.BoneLinkProp {
+			case BoneTranslate, BoneRotate, BoneScale:
+				if cn.hasKeys() {
+					nodes[cn.Bone.ID()] = cn.Bone
+				}
+			}
+		}
+	}
+	out := make([]Obj, 0, len(nodes))
+	for _, n := range nodes {
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID() < out[j].ID() })
+	return out
+}
+
+// TimeSpan returns the times the stack plays between. It is read from the stack's
+// LocalStart and LocalStop, the scene's time span, the stack's take, or failing
+// those the first and last keys of its curves.
+func (as *AnimationStack) TimeSpan() (start, end time.Duration) {
+	start, end = resolveTimeProperty(as, "LocalStart"), resolveTimeProperty(as, "LocalStop")
+	if end > start {
+		return start, end
+	}
+	if as.scene != nil {
+		settings := as.scene.Settings
+		// KTimes are signed, so spans may start before 0
+		if stop, start := int64(settings.TimeSpanStop), int64(settings.TimeSpanStart); stop > start {
+			return fbxTimetoStdTime(start), fbxTimetoStdTime(stop)
+		}
+		if take := as.scene.getTakeInfo(BaseName(as.Name())); take != nil && take.localTimeTo > take.localTimeFrom {
+			return secondsToStdTime(take.localTimeFrom), secondsToStdTime(take.localTimeTo)
+		}
+	}
+	first := true
+	for _, layer := range as.Layers {
+		for _, cn := range layer.CurveNodes {
+			for _, c := range cn.Curves {
+				if c.Curve == nil || len(c.Curve.Times) == 0 {
+					continue
+				}
+				times := c.Curve.Times
+				if first || times[0] < start {
+					start = times[0]
+				}
+				if first || times[len(times)-1] > end {
+					end = times[len(times)-1]
+				}
+				first = false
+			}
+		}
+	}
+	return start, end
+}
+
+func resolveTimeProperty(o Obj, name string) time.Duration {
+	element := resolveProperty(o, name)
+	if element == nil {
+		return 0
+	}
+	prop := element.getProperty(4)
+	if prop == nil {
+		return 0
+	}
+	return fbxTimetoStdTime(prop.integer())
+}
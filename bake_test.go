@@ -0,0 +1,55 @@
+package ofbx
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeSpanNegativeStart(t *testing.T) {
+	// Stacks without their own span use the scene's, which may start before 0
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	stack := scene.AnimationStacks[0]
+	resolveProperty(stack, "LocalStop").Properties[4] = NewLongProperty(0)
+	scene.Settings.TimeSpanStart = uint64(secondsToFbxTime(-1))
+	start, end := stack.TimeSpan()
+	require.Equal(t, -time.Second, start)
+	require.Equal(t, time.Second, end)
+}
+
+func TestBake(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	stack := scene.AnimationStacks[0]
+	start, end := stack.TimeSpan()
+	require.Equal(t, time.Duration(0), start)
+	require.Equal(t, time.Second, end)
+
+	baked := stack.Bake(0, BakeOptions{})
+	require.Equal(t, "Take 001", baked.Name)
+	require.Equal(t, 24.0, baked.FrameRate)
+	require.Len(t, baked.Times, 25)
+	require.Equal(t, time.Second, baked.Duration())
+	require.Len(t, baked.Tracks, 1)
+	track := baked.Tracks[0]
+	require.Equal(t, scene.RootNode.FindByName("Bone"), track.Node)
+	require.Len(t, track.Rotations, 25)
+	angle := 90.5 * math.Pi / 180
+	last := track.Rotations[24]
+	require.InDelta(t, math.Sin(angle/2), last.Z, 1e-6)
+	require.InDelta(t, math.Cos(angle/2), last.W, 1e-6)
+	require.InDelta(t, 2, track.Translations[24].Y(), 1e-9)
+	require.InDelta(t, 1, track.Scales[12].X(), 1e-9)
+
+	baked = stack.Bake(4, BakeOptions{Start: 500 * time.Millisecond, End: 2 * time.Second, IncludeStatic: true})
+	require.Equal(t, []time.Duration{
+		500 * time.Millisecond, 750 * time.Millisecond, time.Second, 1250 * time.Millisecond,
+		1500 * time.Millisecond, 1750 * time.Millisecond, 2 * time.Second,
+	}, baked.Times)
+	require.Len(t, baked.Tracks, 3)
+	require.Equal(t, Obj(scene.Meshes[0]), baked.Tracks[0].Node)
+	require.InDelta(t, 1.5, baked.Tracks[0].Translations[3].X(), 1e-9)
+	// Past the last key the bone holds its final pose
+	require.Equal(t, baked.Tracks[2].Rotations[2], baked.Tracks[2].Rotations[6])
+}
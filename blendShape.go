@@ -0,0 +1,240 @@
+package ofbx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/pkg/errors"
+)
+
+// BlendShape is a deformer which morphs a Geometry towards the Shapes of its channels
+type BlendShape struct {
+	Object
+	Channels []*BlendShapeChannel
+}
+
+// NewBlendShape creates a new BlendShape with no channels
+func NewBlendShape(scene *Scene, element *Element) *BlendShape {
+	return &BlendShape{Object: *NewObject(scene, element)}
+}
+
+// Type returns BLEND_SHAPE
+func (bs *BlendShape) Type() Type {
+	return BLEND_SHAPE
+}
+
+func (bs *BlendShape) String() string {
+	return bs.stringPrefix("")
+}
+
+func (bs *BlendShape) stringPrefix(prefix string) string {
+	s := prefix + "BlendShape: " + bs.Name() + "\n"
+	for _, c := range bs.Channels {
+		s += c.stringPrefix(prefix + "\t")
+	}
+	return s
+}
+
+// BlendShapeChannel is one morph of a BlendShape. With more than one Shape the
+// later ones are in-between targets, reached as DeformPercent passes their FullWeights.
+type BlendShapeChannel struct {
+	Object
+	BlendShape *BlendShape
+	Shapes     []*Shape
+	// DeformPercent is how far the channel is applied, from 0 to 100
+	DeformPercent float64
+	// FullWeights holds the DeformPercent at which each Shape is fully applied
+	FullWeights []float64
+}
+
+// NewBlendShapeChannel creates a new BlendShapeChannel with no shapes
+func NewBlendShapeChannel(scene *Scene, element *Element) *BlendShapeChannel {
+	return &BlendShapeChannel{Object: *NewObject(scene, element)}
+}
+
+// Type returns BLEND_SHAPE_CHANNEL
+func (c *BlendShapeChannel) Type() Type {
+	return BLEND_SHAPE_CHANNEL
+}
+
+func (c *BlendShapeChannel) String() string {
+	return c.stringPrefix("")
+}
+
+func (c *BlendShapeChannel) stringPrefix(prefix string) string {
+	s := prefix + "BlendShapeChannel: " + c.Name() + fmt.Sprintf(" percent=%v full_weights=%v", c.DeformPercent, c.FullWeights) + "\n"
+	for _, shape := range c.Shapes {
+		s += shape.stringPrefix(prefix + "\t")
+	}
+	return s
+}
+
+func parseBlendShapeChannel(scene *Scene, element *Element) (*BlendShapeChannel, error) {
+	c := NewBlendShapeChannel(scene, element)
+	percent := 0.0
+	if prop := findSingleChildProperty(element, "DeformPercent"); prop != nil {
+		percent = prop.number()
+	}
+	c.DeformPercent = resolveNumberProperty(c, "DeformPercent", percent)
+	if prop := findSingleChildProperty(element, "FullWeights"); prop != nil {
+		var err error
+		if c.FullWeights, err = parseBinaryArrayFloat64(prop); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse FullWeights")
+		}
+	}
+	return c, nil
+}
+
+// fullWeight returns the DeformPercent at which shape i is fully applied. Missing
+// full weights spread the shapes evenly up to 100.
+func (c *BlendShapeChannel) fullWeight(i int) float64 {
+	if len(c.FullWeights) == len(c.Shapes) {
+		return c.FullWeights[i]
+	}
+	return 100 * float64(i+1) / float64(len(c.Shapes))
+}
+
+// ShapeWeights returns how much of each Shape is applied at the given deform percent.
+// Between two shapes' full weights the percent blends from one to the next; below the
+// first it blends from the base geometry, and past the last it extrapolates.
+func (c *BlendShapeChannel) ShapeWeights(percent float64) []float64 {
+	weights := make([]float64, len(c.Shapes))
+	if len(weights) == 0 {
+		return weights
+	}
+	prev := 0.0
+	for i := range c.Shapes {
+		full := c.fullWeight(i)
+		if percent <= full || i == len(c.Shapes)-1 {
+			if full == prev {
+				weights[i] = 1
+				return weights
+			}
+			f := (percent - prev) / (full - prev)
+			weights[i] = f
+			if i > 0 {
+				weights[i-1] = 1 - f
+			}
+			return weights
+		}
+		prev = full
+	}
+	return weights
+}
+
+// DeformPercentAt returns the channel's deform percent at time t of the given stack,
+// with the stack's layers blended as they are for node transforms
+func (c *BlendShapeChannel) DeformPercentAt(stack *AnimationStack, t time.Duration) float64 {
+	if stack == nil {
+		return c.DeformPercent
+	}
+	return stack.evaluateProperty(c, "DeformPercent", c.DeformPercent, t)
+}
+
+// Shape is a morph target, holding offsets from a base Geometry for some of its control points
+type Shape struct {
+	Object
+	// Indices are the control points of the base Geometry the shape moves
+	Indices []int
+	// Vertices and Normals are offsets for each of Indices. Normals may be empty.
+	Vertices []floatgeom.Point3
+	Normals  []floatgeom.Point3
+}
+
+// NewShape creates a new empty Shape
+func NewShape(scene *Scene, element *Element) *Shape {
+	return &Shape{Object: *NewObject(scene, element)}
+}
+
+// Type returns SHAPE
+func (s *Shape) Type() Type {
+	return SHAPE
+}
+
+func (s *Shape) String() string {
+	return s.stringPrefix("")
+}
+
+func (s *Shape) stringPrefix(prefix string) string {
+	return prefix + "Shape: " + s.Name() + fmt.Sprintf(" indices=%v", s.Indices) + "\n"
+}
+
+func parseShape(scene *Scene, element *Element) (*Shape, error) {
+	shape := NewShape(scene, element)
+	var err error
+	if prop := findSingleChildProperty(element, "Indexes"); prop != nil {
+		if shape.Indices, err = parseBinaryArrayInt(prop); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse shape indices")
+		}
+	}
+	if prop := findSingleChildProperty(element, "Vertices"); prop != nil {
+		if shape.Vertices, err = parseDoubleVecDataVec3(prop); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse shape vertices")
+		}
+	}
+	if prop := findSingleChildProperty(element, "Normals"); prop != nil {
+		if shape.Normals, err = parseDoubleVecDataVec3(prop); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse shape normals")
+		}
+	}
+	if len(shape.Vertices) != len(shape.Indices) {
+		return nil, errors.New("Shape vertex and index counts differ")
+	}
+	if len(shape.Normals) != 0 && len(shape.Normals) != len(shape.Indices) {
+		return nil, errors.New("Shape normal and index counts differ")
+	}
+	return shape, nil
+}
+
+// MorphedVertices applies the Geometry's blend shapes at time t of the given stack, or
+// their static deform percents if stack is nil. It returns the morphed Vertices, and a
+// normal for each of the Geometry's Normals, in the Geometry's space.
+func (g *Geometry) MorphedVertices(stack *AnimationStack, t time.Duration) (positions, normals []floatgeom.Point3) {
+	positions = make([]floatgeom.Point3, len(g.Vertices))
+	copy(positions, g.Vertices)
+	var normalOffsets map[int]floatgeom.Point3
+	for _, bs := range g.BlendShapes {
+		for _, c := range bs.Channels {
+			weights := c.ShapeWeights(c.DeformPercentAt(stack, t))
+			for si, shape := range c.Shapes {
+				w := weights[si]
+				if w == 0 {
+					continue
+				}
+				for j, v := range shape.Indices {
+					if v < 0 || v >= len(positions) {
+						continue
+					}
+					positions[v] = positions[v].Add(shape.Vertices[j].MulConst(w))
+					if len(shape.Normals) != 0 {
+						if normalOffsets == nil {
+							normalOffsets = make(map[int]floatgeom.Point3)
+						}
+						normalOffsets[v] = normalOffsets[v].Add(shape.Normals[j].MulConst(w))
+					}
+				}
+			}
+		}
+	}
+
+	if len(g.Normals) != 0 {
+		normals = make([]floatgeom.Point3, len(g.Normals))
+		copy(normals, g.Normals)
+		if normalOffsets != nil {
+			pv := 0
+			for _, face := range g.Faces {
+				for _, ci := range face {
+					if pv >= len(normals) {
+						break
+					}
+					if off, ok := normalOffsets[ci]; ok {
+						normals[pv] = normals[pv].Add(off).Normalize()
+					}
+					pv++
+				}
+			}
+		}
+	}
+	return positions, normals
+}
@@ -0,0 +1,64 @@
+package ofbx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlendShapes(t *testing.T) {
+	scene := loadTestScene(t, "testdata/morph.fbx")
+
+	mesh := scene.Meshes[0]
+	geom := mesh.Geometry
+	require.Len(t, geom.BlendShapes, 1)
+	bs := geom.BlendShapes[0]
+	require.Len(t, bs.Channels, 1)
+	channel := bs.Channels[0]
+	require.Equal(t, bs, channel.BlendShape)
+	require.Equal(t, 25.0, channel.DeformPercent)
+	require.Equal(t, []float64{50, 100}, channel.FullWeights)
+	require.Len(t, channel.Shapes, 2)
+	shape := channel.Shapes[0]
+	require.Equal(t, []int{2, 3}, shape.Indices)
+	require.Equal(t, floatgeom.Point3{0, 0, 1}, shape.Vertices[0])
+	require.Len(t, shape.Normals, 2)
+	require.Empty(t, channel.Shapes[1].Normals)
+
+	require.Equal(t, []float64{0.5, 0}, channel.ShapeWeights(25))
+	require.Equal(t, []float64{0.5, 0.5}, channel.ShapeWeights(75))
+	require.Equal(t, []float64{-0.5, 1.5}, channel.ShapeWeights(125))
+
+	stack := scene.AnimationStacks[0]
+	require.Equal(t, 25.0, channel.DeformPercentAt(nil, 0))
+	require.Equal(t, 50.0, channel.DeformPercentAt(stack, 500*time.Millisecond))
+
+	positions, normals := geom.MorphedVertices(nil, 0)
+	require.Equal(t, floatgeom.Point3{1, 1, 0.5}, positions[2])
+	require.Equal(t, geom.Vertices[1], positions[1])
+	require.InDelta(t, -0.4472, normals[2].Y(), 1e-4)
+	require.Equal(t, geom.Normals[0], normals[0])
+
+	// At full weight only the second shape applies
+	positions, normals = geom.MorphedVertices(stack, time.Second)
+	require.Equal(t, floatgeom.Point3{0, 1, 3}, positions[3])
+	require.Equal(t, floatgeom.Point3{0, 0, 1}, normals[3])
+
+	// Skinning starts from the morphed geometry
+	positions, _ = mesh.SkinnedVertices(stack, time.Second)
+	require.Equal(t, floatgeom.Point3{0, 1, 8}, positions[3])
+}
+
+func TestBlendShapeChannelIntegerPercent(t *testing.T) {
+	root, err := tokenizeText(textCursor(`Deformer: 300, "SubDeformer::Smile", "BlendShapeChannel" {
+	DeformPercent: 0
+}`))
+	require.Nil(t, err)
+	element := root.Children[0]
+	element.Children[0].Properties[0] = NewIntegerProperty(40)
+	channel, err := parseBlendShapeChannel(nil, element)
+	require.Nil(t, err)
+	require.Equal(t, 40.0, channel.DeformPercent)
+}
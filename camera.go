@@ -0,0 +1,209 @@
+package ofbx
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// ProjectionType is how a Camera projects the scene
+type ProjectionType int
+
+// Projection type values
+const (
+	ProjectionPerspective  ProjectionType = iota
+	ProjectionOrthographic ProjectionType = iota
+)
+
+// ApertureMode is which of a Camera's properties its field of view is taken from
+type ApertureMode int
+
+// Aperture mode values
+const (
+	// ApertureHorizAndVert uses FieldOfViewX and FieldOfViewY
+	ApertureHorizAndVert ApertureMode = iota
+	// ApertureHorizontal uses FieldOfView as the horizontal field of view
+	ApertureHorizontal ApertureMode = iota
+	// ApertureVertical uses FieldOfView as the vertical field of view
+	ApertureVertical ApertureMode = iota
+	// ApertureFocalLength computes the field of view from FocalLength and the film size
+	ApertureFocalLength ApertureMode = iota
+)
+
+// Camera is a Model with a camera attribute. Like the FBX SDK's cameras it looks down
+// its local X axis with Y up; its Object transforms place it in the scene.
+// https://help.autodesk.com/view/FBX/2017/ENU/?guid=__cpp_ref_class_fbx_camera_html
+type Camera struct {
+	Object
+	ProjectionType ProjectionType
+	ApertureMode   ApertureMode
+	// FieldOfView, FieldOfViewX and FieldOfViewY are in degrees
+	FieldOfView  float64
+	FieldOfViewX float64
+	FieldOfViewY float64
+	// FocalLength is in millimeters
+	FocalLength float64
+	// FilmWidth and FilmHeight are the film aperture in inches
+	FilmWidth  float64
+	FilmHeight float64
+	// AspectWidth and AspectHeight are the resolution the camera renders at
+	AspectWidth  float64
+	AspectHeight float64
+	NearPlane    float64
+	FarPlane     float64
+	OrthoZoom    float64
+	// InterestPosition is where the camera looks when it has no Target
+	InterestPosition floatgeom.Point3
+	// Target is the node the camera is aimed at, or nil
+	Target Obj
+}
+
+// NewCamera creates a new Camera with the FBX SDK's default properties
+func NewCamera(scene *Scene, element *Element) *Camera {
+	c := &Camera{
+		Object:       *NewObject(scene, element),
+		ApertureMode: ApertureVertical,
+		FieldOfView:  25.114999,
+		FieldOfViewX: 40,
+		FieldOfViewY: 40,
+		FocalLength:  34.89327,
+		FilmWidth:    0.816,
+		FilmHeight:   0.612,
+		AspectWidth:  320,
+		AspectHeight: 200,
+		NearPlane:    10,
+		FarPlane:     4000,
+		OrthoZoom:    1,
+	}
+	c.Object.isNode = true
+	return c
+}
+
+// Type returns CAMERA
+func (c *Camera) Type() Type {
+	return CAMERA
+}
+
+// postProcess reads the camera's properties from its node attribute, which is
+// only connected once all objects are parsed
+func (c *Camera) postProcess() bool {
+	attr := c.NodeAttribute()
+	if attr == nil {
+		return true
+	}
+	c.ProjectionType = ProjectionType(resolveEnumProperty(attr, "CameraProjectionType", int(c.ProjectionType)))
+	c.ApertureMode = ApertureMode(resolveEnumProperty(attr, "ApertureMode", int(c.ApertureMode)))
+	c.FieldOfView = resolveNumberProperty(attr, "FieldOfView", c.FieldOfView)
+	c.FieldOfViewX = resolveNumberProperty(attr, "FieldOfViewX", c.FieldOfViewX)
+	c.FieldOfViewY = resolveNumberProperty(attr, "FieldOfViewY", c.FieldOfViewY)
+	c.FocalLength = resolveNumberProperty(attr, "FocalLength", c.FocalLength)
+	c.FilmWidth = resolveNumberProperty(attr, "FilmWidth", c.FilmWidth)
+	c.FilmHeight = resolveNumberProperty(attr, "FilmHeight", c.FilmHeight)
+	c.AspectWidth = resolveNumberProperty(attr, "AspectWidth", c.AspectWidth)
+	c.AspectHeight = resolveNumberProperty(attr, "AspectHeight", c.AspectHeight)
+	c.NearPlane = resolveNumberProperty(attr, "NearPlane", c.NearPlane)
+	c.FarPlane = resolveNumberProperty(attr, "FarPlane", c.FarPlane)
+	c.OrthoZoom = resolveNumberProperty(attr, "OrthoZoom", c.OrthoZoom)
+	c.InterestPosition = resolveVec3Property(attr, "InterestPosition", c.InterestPosition)
+	return true
+}
+
+// AspectRatio returns the width over height the camera renders at, falling back to
+// its film aperture's
+func (c *Camera) AspectRatio() float64 {
+	if c.AspectWidth > 0 && c.AspectHeight > 0 {
+		return c.AspectWidth / c.AspectHeight
+	}
+	if c.FilmWidth > 0 && c.FilmHeight > 0 {
+		return c.FilmWidth / c.FilmHeight
+	}
+	return 1
+}
+
+// FocalLengthAt returns the camera's focal length at time t of the given stack, or its
+// static focal length if stack is nil
+func (c *Camera) FocalLengthAt(stack *AnimationStack, t time.Duration) float64 {
+	return c.propertyAt(stack, "FocalLength", c.FocalLength, t)
+}
+
+// FieldOfViewAt returns the camera's horizontal and vertical fields of view in degrees
+// at time t of the given stack, or its static ones if stack is nil. Whichever one the
+// ApertureMode doesn't give is derived through the AspectRatio; in ApertureFocalLength
+// mode the vertical one comes from the FocalLength and FilmHeight.
+func (c *Camera) FieldOfViewAt(stack *AnimationStack, t time.Duration) (x, y float64) {
+	aspect := c.AspectRatio()
+	switch c.ApertureMode {
+	case ApertureHorizAndVert:
+		return c.propertyAt(stack, "FieldOfViewX", c.FieldOfViewX, t), c.propertyAt(stack, "FieldOfViewY", c.FieldOfViewY, t)
+	case ApertureHorizontal:
+		x = c.propertyAt(stack, "FieldOfView", c.FieldOfView, t)
+		return x, scaleFieldOfView(x, 1/aspect)
+	case ApertureFocalLength:
+		focal := c.FocalLengthAt(stack, t)
+		if focal <= 0 {
+			return 0, 0
+		}
+		// Film sizes are in inches and focal lengths in millimeters
+		y = 2 * math.Atan(c.FilmHeight*25.4/2/focal) * 180 / math.Pi
+		return scaleFieldOfView(y, aspect), y
+	default:
+		y = c.propertyAt(stack, "FieldOfView", c.FieldOfView, t)
+		return scaleFieldOfView(y, aspect), y
+	}
+}
+
+// scaleFieldOfView returns the field of view in degrees across an extent scale
+// times as long as the one fov spans
+func scaleFieldOfView(fov, scale float64) float64 {
+	half := fov / 2 * math.Pi / 180
+	return 2 * math.Atan(math.Tan(half)*scale) * 180 / math.Pi
+}
+
+// InterestAt returns the point the camera looks at at time t of the given stack: its
+// Target's position if it has one, otherwise its InterestPosition
+func (c *Camera) InterestAt(stack *AnimationStack, t time.Duration) floatgeom.Point3 {
+	if c.Target != nil {
+		return c.Target.GlobalTransformAt(stack, t).TransformPoint(floatgeom.Point3{})
+	}
+	return c.InterestPosition
+}
+
+// propertyAt returns the animated value of one of the camera's attribute properties
+func (c *Camera) propertyAt(stack *AnimationStack, prop string, value float64, t time.Duration) float64 {
+	if stack == nil {
+		return value
+	}
+	if attr := c.NodeAttribute(); attr != nil {
+		value = stack.evaluateProperty(attr, prop, value, t)
+	}
+	return stack.evaluateProperty(c, prop, value, t)
+}
+
+func (c *Camera) String() string {
+	return c.stringPrefix("")
+}
+
+func (c *Camera) stringPrefix(prefix string) string {
+	s := prefix + "Camera: " + c.Name() + "\n"
+	s += prefix + "\t" + fmt.Sprintf("projection=%d aperture_mode=%d fov=%v focal_length=%v near=%v far=%v", c.ProjectionType, c.ApertureMode, c.FieldOfView, c.FocalLength, c.NearPlane, c.FarPlane) + "\n"
+	if c.Target != nil {
+		s += prefix + "\t" + fmt.Sprintf("target=%d", c.Target.ID()) + "\n"
+	}
+	s += c.Object.stringPrefix(prefix + "\t")
+	return s
+}
+
+// Cameras returns the scene's cameras, ordered by id
+func (s *Scene) Cameras() []*Camera {
+	out := make([]*Camera, 0)
+	for _, o := range s.ObjectMap {
+		if c, ok := o.(*Camera); ok {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID() < out[j].ID() })
+	return out
+}
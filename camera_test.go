@@ -0,0 +1,48 @@
+package ofbx
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCameras(t *testing.T) {
+	scene := loadTestScene(t, "testdata/camera.fbx")
+
+	cameras := scene.Cameras()
+	require.Len(t, cameras, 2)
+	shot, overview := cameras[0], cameras[1]
+	require.Equal(t, "Model::Shot", shot.Name())
+	require.Equal(t, CAMERA, shot.Type())
+	require.True(t, shot.IsNode())
+	require.Equal(t, ProjectionPerspective, shot.ProjectionType)
+	require.Equal(t, ApertureFocalLength, shot.ApertureMode)
+	require.Equal(t, 35.0, shot.FocalLength)
+	require.Equal(t, 0.1, shot.NearPlane)
+	require.Equal(t, 1000.0, shot.FarPlane)
+	require.InDelta(t, 16.0/9, shot.AspectRatio(), 1e-9)
+	require.Equal(t, floatgeom.Point3{0, 2, 10}, shot.GlobalTransform().TransformPoint(floatgeom.Point3{}))
+
+	require.NotNil(t, shot.Target)
+	require.Equal(t, "Model::Target", shot.Target.Name())
+	require.Equal(t, floatgeom.Point3{1, 0, 0}, shot.InterestAt(nil, 0))
+	// Cameras aren't parented to the nodes they look at
+	require.Equal(t, ROOT, shot.Target.Parent().Type())
+
+	stack := scene.AnimationStacks[0]
+	require.Equal(t, 35.0, shot.FocalLengthAt(nil, 0))
+	require.Equal(t, 60.0, shot.FocalLengthAt(stack, 500*time.Millisecond))
+	_, y := shot.FieldOfViewAt(stack, time.Second)
+	require.InDelta(t, 2*math.Atan(0.945*25.4/2/85)*180/math.Pi, y, 1e-9)
+
+	require.Equal(t, ProjectionOrthographic, overview.ProjectionType)
+	require.Equal(t, 2.0, overview.OrthoZoom)
+	require.Nil(t, overview.Target)
+	require.Equal(t, floatgeom.Point3{}, overview.InterestAt(nil, 0))
+	x, y := overview.FieldOfViewAt(stack, 0)
+	require.Equal(t, 90.0, x)
+	require.InDelta(t, 2*math.Atan(0.5)*180/math.Pi, y, 1e-9)
+}
@@ -6,17 +6,33 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ClusterLinkMode is how a cluster's weights are taken into account
+type ClusterLinkMode int
+
+// ClusterLinkMode options
+const (
+	// LinkNormalize divides each vertex's weighted transforms by its total weight
+	LinkNormalize ClusterLinkMode = iota
+	// LinkAdditive applies the cluster's transform on top of those of other clusters
+	LinkAdditive ClusterLinkMode = iota
+	// LinkTotalOne leaves each vertex's missing weight to its unskinned transform
+	LinkTotalOne ClusterLinkMode = iota
+)
+
 // Cluster is an entity which acts on a subset of a geometry's control points
 // For each control point that the cluster acts on, the intensity of the cluster's action is modulated by a weight. The link mode (ELinkMode) specifies how the weights are taken into account.
 type Cluster struct {
 	Object
 
-	Link          Obj
-	Skin          *Skin
+	Link Obj
+	Skin *Skin
+	// Indices are the control points, indices into the Geometry's Vertices, the
+	// cluster acts on, with the matching Weights
 	Indices       []int
 	Weights       []float64
 	Transform     Matrix
 	TransformLink Matrix
+	LinkMode      ClusterLinkMode
 }
 
 // NewCluster creates a new empty Cluster object
@@ -49,7 +65,7 @@ func (c *Cluster) Type() Type {
 }
 
 // postProcess adds the additional fields that clusters have over just object fields.
-// In this case its setting up indicies and weights
+// In this case its setting up indicies, into the geometry's Vertices, and weights
 func (c *Cluster) postProcess() bool {
 	element := c.Element()
 	geom, ok := resolveObjectLinkReverse(c.Skin, GEOMETRY).(*Geometry)
@@ -81,15 +97,11 @@ func (c *Cluster) postProcess() bool {
 	c.Indices = make([]int, 0, iLen)
 
 	for i := 0; i < iLen; i++ {
-		n := &geom.newVerts[oldIndices[i]] //was a geometryimpl NewVertex
-		if n.index == -1 {
-			continue // skip vertices which aren't indexed.
-		}
-		for n != nil {
-			c.Indices = append(c.Indices, n.index)
-			c.Weights = append(c.Weights, oldWeights[i])
-			n = n.next
+		if oldIndices[i] < 0 || oldIndices[i] >= len(geom.Vertices) {
+			continue // skip vertices which aren't in the geometry
 		}
+		c.Indices = append(c.Indices, oldIndices[i])
+		c.Weights = append(c.Weights, oldWeights[i])
 	}
 	return true
 
@@ -97,6 +109,14 @@ func (c *Cluster) postProcess() bool {
 
 func parseCluster(scene *Scene, element *Element) (*Cluster, error) {
 	obj := NewCluster(scene, element)
+	if mode := findSingleChildProperty(element, "Mode"); mode != nil {
+		switch mode.value.String() {
+		case "Additive":
+			obj.LinkMode = LinkAdditive
+		case "Total1", "TotalOne":
+			obj.LinkMode = LinkTotalOne
+		}
+	}
 
 	prop := findChildProperty(element, "TransformLink")
 	if prop != nil {
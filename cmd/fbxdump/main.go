@@ -0,0 +1,140 @@
+// Command fbxdump prints the raw element tree of a binary or ASCII FBX file.
+//
+// Usage:
+//
+//	fbxdump [-depth n] [-path Objects/Geometry] [-arrays n] file.fbx
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/oakmound/ofbx"
+)
+
+type options struct {
+	// maxDepth limits how many levels of elements are printed, 0 prints all of them
+	maxDepth int
+	// path filters the printed elements to those under a slash separated list of element IDs
+	path []string
+	// arrayLimit is the number of array values printed, -1 prints all of them
+	arrayLimit int
+}
+
+func main() {
+	depth := flag.Int("depth", 0, "maximum depth of elements to print, 0 for no limit")
+	path := flag.String("path", "", "only print elements under this path of element IDs, e.g. Objects/Geometry")
+	arrays := flag.Int("arrays", 8, "number of array values to print, -1 for all of them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] file.fbx\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	root, err := ofbx.ReadElements(f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	opts := options{maxDepth: *depth, arrayLimit: *arrays}
+	if *path != "" {
+		opts.path = strings.Split(strings.Trim(*path, "/"), "/")
+	}
+	w := bufio.NewWriter(os.Stdout)
+	dump(w, root, opts)
+	w.Flush()
+}
+
+// dump prints the root's children, which are the file's top level elements
+func dump(w io.Writer, root *ofbx.Element, opts options) {
+	for _, e := range root.Children {
+		dumpElement(w, e, 1, opts)
+	}
+}
+
+func dumpElement(w io.Writer, e *ofbx.Element, depth int, opts options) {
+	id := ""
+	if e.ID != nil {
+		id = e.ID.String()
+	}
+	// Elements above the end of the path are matched, but not printed
+	indentDepth := depth - 1
+	if depth <= len(opts.path) {
+		if id != opts.path[depth-1] {
+			return
+		}
+		if depth < len(opts.path) {
+			for _, c := range e.Children {
+				dumpElement(w, c, depth+1, opts)
+			}
+			return
+		}
+	}
+	if len(opts.path) != 0 {
+		indentDepth = depth - len(opts.path)
+	}
+	if opts.maxDepth != 0 && indentDepth >= opts.maxDepth {
+		return
+	}
+
+	indent := strings.Repeat("  ", indentDepth)
+	props := make([]string, len(e.Properties))
+	for i, p := range e.Properties {
+		props[i] = formatProperty(p, opts.arrayLimit)
+	}
+	fmt.Fprintf(w, "%s@%d %s: %s\n", indent, e.Offset, id, strings.Join(props, ", "))
+	for _, c := range e.Children {
+		dumpElement(w, c, depth+1, opts)
+	}
+}
+
+func formatProperty(p *ofbx.Property, limit int) string {
+	if !p.Type.IsArray() {
+		s := p.String()
+		if p.Type == ofbx.STRING || p.Type == ofbx.RAWSTRING {
+			s = strconv.Quote(s)
+		}
+		return string(p.Type) + " " + s
+	}
+
+	var vals []string
+	switch p.Type {
+	case ofbx.ArrayDOUBLE, ofbx.ArrayFLOAT:
+		fs, err := p.Float64s()
+		if err != nil {
+			return string(p.Type) + " " + err.Error()
+		}
+		for _, f := range fs {
+			vals = append(vals, strconv.FormatFloat(f, 'g', -1, 64))
+		}
+	default:
+		is, err := p.Int64s()
+		if err != nil {
+			return string(p.Type) + " " + err.Error()
+		}
+		for _, i := range is {
+			vals = append(vals, strconv.FormatInt(i, 10))
+		}
+	}
+	if limit >= 0 && len(vals) > limit {
+		vals = append(vals[:limit], "...")
+	}
+	return fmt.Sprintf("%c[%d] encoding=%d stored=%d {%s}",
+		p.Type, p.Count, p.Encoding, p.CompressedLength(), strings.Join(vals, ","))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oakmound/ofbx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	f, err := os.Open("../../testdata/cube.fbx")
+	require.Nil(t, err)
+	defer f.Close()
+	root, err := ofbx.ReadElements(f)
+	require.Nil(t, err)
+
+	buff := &bytes.Buffer{}
+	dump(buff, root, options{maxDepth: 1})
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, len(root.Children))
+	require.True(t, strings.HasPrefix(lines[0], "@"))
+
+	buff.Reset()
+	dump(buff, root, options{path: []string{"Objects", "Geometry", "Vertices"}, arrayLimit: 2})
+	require.Regexp(t, `^@\d+ Vertices: d\[24\] encoding=0 stored=192 \{-1,-1,\.\.\.\}\n$`, buff.String())
+
+	buff.Reset()
+	dump(buff, root, options{path: []string{"Objects", "Model"}, maxDepth: 1, arrayLimit: -1})
+	require.Contains(t, buff.String(), `Model: L 100, S "Model::Cube", S "Mesh"`)
+	require.Equal(t, 3, strings.Count(buff.String(), "\n"))
+}
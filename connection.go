@@ -27,6 +27,26 @@ func (ct ConnectionType) String() string {
 	return "OBJ->PROP"
 }
 
+// Type returns whether the connection is to an Object or a Property
+func (c *Connection) Type() ConnectionType {
+	return c.typ
+}
+
+// From returns the ID of the connected child Object
+func (c *Connection) From() uint64 {
+	return c.from
+}
+
+// To returns the ID of the parent Object
+func (c *Connection) To() uint64 {
+	return c.to
+}
+
+// Property returns the name of the property connected to, for PropConn connections
+func (c *Connection) Property() string {
+	return c.property
+}
+
 func (c *Connection) String() string {
 	s := "Connection: " + c.typ.String()
 	s += " from=" + fmt.Sprintf("%d", c.from)
@@ -5,24 +5,35 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"strconv"
 )
 
 // DataView leftover concept that knows how to present different type sof data
 type DataView struct {
 	bytes.Reader
+	// isText is set for values read from ASCII FBX, which hold the literal
+	// text of a number rather than its little endian encoding
+	isText bool
 }
 
 // NewDataView creates a new Dataview and the underlying bytes reader on the given string
 func NewDataView(s string) *DataView {
 	return &DataView{
-		*bytes.NewReader([]byte(s)),
+		Reader: *bytes.NewReader([]byte(s)),
 	}
 }
 
 // BufferDataView creates a DataView from the delivered buffer
 func BufferDataView(buff *bytes.Buffer) *DataView {
 	return &DataView{
-		*bytes.NewReader(buff.Bytes()),
+		Reader: *bytes.NewReader(buff.Bytes()),
+	}
+}
+
+func textDataView(s string) *DataView {
+	return &DataView{
+		Reader: *bytes.NewReader([]byte(s)),
+		isText: true,
 	}
 }
 
@@ -38,7 +49,19 @@ func (dv *DataView) String() string {
 	return string(data)
 }
 
+// bytes returns the full underlying data, regardless of how much has been read
+func (dv *DataView) bytes() []byte {
+	dv.Seek(0, io.SeekStart)
+	data := make([]byte, dv.Len())
+	dv.Read(data)
+	dv.Seek(0, io.SeekStart)
+	return data
+}
+
 func (dv *DataView) touint64() uint64 {
+	if dv.isText {
+		return uint64(dv.textInt64())
+	}
 	var i uint64
 	err := binary.Read(dv, binary.LittleEndian, &i)
 	if err != nil && err != io.EOF {
@@ -48,6 +71,9 @@ func (dv *DataView) touint64() uint64 {
 }
 
 func (dv *DataView) toint64() int64 {
+	if dv.isText {
+		return dv.textInt64()
+	}
 	var i int64
 	err := binary.Read(dv, binary.LittleEndian, &i)
 	if err != nil && err != io.EOF {
@@ -57,6 +83,9 @@ func (dv *DataView) toint64() int64 {
 }
 
 func (dv *DataView) toInt32() int32 {
+	if dv.isText {
+		return int32(dv.textInt64())
+	}
 	var i int32
 	err := binary.Read(dv, binary.LittleEndian, &i)
 	if err != nil && err != io.EOF {
@@ -66,6 +95,9 @@ func (dv *DataView) toInt32() int32 {
 }
 
 func (dv *DataView) touint32() uint32 {
+	if dv.isText {
+		return uint32(dv.textInt64())
+	}
 	var i uint32
 	err := binary.Read(dv, binary.LittleEndian, &i)
 	if err != nil && err != io.EOF {
@@ -75,6 +107,9 @@ func (dv *DataView) touint32() uint32 {
 }
 
 func (dv *DataView) toDouble() float64 {
+	if dv.isText {
+		return dv.textFloat64()
+	}
 	var i float64
 	err := binary.Read(dv, binary.LittleEndian, &i)
 	if err != nil && err != io.EOF {
@@ -84,6 +119,9 @@ func (dv *DataView) toDouble() float64 {
 }
 
 func (dv *DataView) toFloat() float32 {
+	if dv.isText {
+		return float32(dv.textFloat64())
+	}
 	var i float32
 	err := binary.Read(dv, binary.LittleEndian, &i)
 	if err != nil && err != io.EOF {
@@ -93,6 +131,9 @@ func (dv *DataView) toFloat() float32 {
 }
 
 func (dv *DataView) toBool() bool {
+	if dv.isText {
+		return dv.textInt64() != 0
+	}
 	var i bool
 	err := binary.Read(dv, binary.LittleEndian, &i)
 	if err != nil && err != io.EOF {
@@ -100,3 +141,24 @@ func (dv *DataView) toBool() bool {
 	}
 	return i
 }
+
+func (dv *DataView) textInt64() int64 {
+	s := dv.String()
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return i
+	}
+	// Object ids are written unsigned and may not fit in an int64
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return int64(u)
+	}
+	return int64(dv.textFloat64())
+}
+
+func (dv *DataView) textFloat64() float64 {
+	f, err := strconv.ParseFloat(dv.String(), 64)
+	if err != nil {
+		fmt.Println("text read failure:", err)
+	}
+	return f
+}
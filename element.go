@@ -7,6 +7,8 @@ type Element struct {
 	ID         *DataView
 	Children   []*Element
 	Properties []*Property
+	// Offset is the byte offset the element started at in the file it was read from
+	Offset int
 }
 
 func (e *Element) getProperty(idx int) *Property {
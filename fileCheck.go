@@ -5,9 +5,10 @@ import (
 	"io"
 )
 
+var binaryMagic = append([]byte("Kaydara FBX Binary  "), 0)
+
 func isBinary(r io.Reader) bool {
-	magic := append([]byte("Kaydara FBX Binary  "), 0)
-	header := make([]byte, len(magic))
+	header := make([]byte, len(binaryMagic))
 	n, err := r.Read(header)
 	if n != len(header) {
 		return false
@@ -15,5 +16,5 @@ func isBinary(r io.Reader) bool {
 	if err != nil {
 		return false
 	}
-	return bytes.Equal(magic, header)
+	return bytes.Equal(binaryMagic, header)
 }
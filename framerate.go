@@ -2,6 +2,7 @@ package ofbx
 
 // FrameRate documented here: http://docs.autodesk.com/FBX/2014/ENU/FBX-SDK-Documentation/index.html?url=cpp_ref/class_fbx_time.html,topicNumber=cpp_ref_class_fbx_time_html29087af6-8c2c-4e9d-aede-7dc5a1c2436c,hash=a837590fd5310ff5df56ffcf7c394787e
 import (
+	"math"
 	"time"
 )
 
@@ -64,13 +65,21 @@ func GetFramerateFromTimeMode(f FrameRate, custom float32) float32 {
 	return -1
 }
 
+// fbxTicksPerSecond is the resolution of FBX time values
+const fbxTicksPerSecond = 46186158000
+
 func fbxTimetoStdTime(value int64) time.Duration {
-	return time.Microsecond * time.Duration(float64(value)/float64(46186.158))
+	return time.Duration(math.Round(float64(value) / fbxTicksPerSecond * float64(time.Second)))
 }
 
 func fbxTimeToSeconds(value int64) float64 {
-	return float64(value) / float64(46186158000)
+	return float64(value) / fbxTicksPerSecond
 }
+
 func secondsToFbxTime(value float64) int64 {
-	return int64(value / 46186158000)
+	return int64(math.Round(value * fbxTicksPerSecond))
+}
+
+func secondsToStdTime(value float64) time.Duration {
+	return time.Duration(math.Round(value * float64(time.Second)))
 }
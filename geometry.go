@@ -30,15 +30,23 @@ const MaxUvs = 4
 //Geometry is the base geometric shape objec that is implemented in forms such as meshes that dictate control point deformations
 type Geometry struct {
 	Object
-	Skin *Skin
-
-	Vertices, Normals, Tangents []floatgeom.Point3
-
-	UVs                 [MaxUvs][]floatgeom.Point2
-	Colors              []floatgeom.Point4
-	Materials, oldVerts []int
-	newVerts            []Vertex
-	Faces               [][]int
+	Skin        *Skin
+	BlendShapes []*BlendShape
+
+	// Vertices are the control points of the geometry
+	Vertices []floatgeom.Point3
+	// Normals, Tangents, Binormals, UVs and Colors have one value per polygon vertex,
+	// in the order the vertices appear in Faces
+	Normals, Tangents, Binormals []floatgeom.Point3
+
+	UVs [MaxUvs][]floatgeom.Point2
+	// UVNames are the names of the UV sets in UVs, like map1
+	UVNames [MaxUvs]string
+	Colors  []floatgeom.Point4
+	// Materials holds, for each face, an index into the Mesh's Materials
+	Materials []int
+	// Faces are polygons of indices into Vertices
+	Faces [][]int
 }
 
 func (g *Geometry) String() string {
@@ -156,29 +164,10 @@ func (g *Geometry) stringPrefix(prefix string) string {
 	return s
 }
 
-// Vertex hey wit its a linked list of indices!.....
-type Vertex struct {
-	index int //should start as -1
-	next  *Vertex
-}
-
-func (nv *Vertex) add(index int) {
-	if nv.index == -1 {
-		//TODO: change this cuz we aint implementing it this way. Really its checking if the newvertex exists...
-		nv.index = index
-	} else if nv.next != nil {
-		nv.next.add(index)
-	} else {
-		nv.next = &Vertex{-1, nil}
-		nv.next.index = index
-	}
-}
-
 // NewGeometry makes a stub Geometry
 func NewGeometry(scene *Scene, element *Element) *Geometry {
 	g := &Geometry{}
 	g.Object = *NewObject(scene, element)
-	g.oldVerts = make([]int, 0)
 	return g
 }
 
@@ -187,34 +176,6 @@ func (g *Geometry) Type() Type {
 	return GEOMETRY
 }
 
-func (g *Geometry) triangulate(indices []int) []int {
-	old := make([]int, 0)
-	polyIdx := 0
-	for i := 0; i < len(indices); i++ {
-		idx := indices[i]
-		if idx < 0 {
-			idx = (-idx) - 1
-		}
-
-		if polyIdx <= 2 {
-			g.oldVerts = append(g.oldVerts, idx)
-			old = append(old, i)
-		} else {
-			g.oldVerts = append(g.oldVerts, indices[i-polyIdx])
-			old = append(old, i-polyIdx)
-			g.oldVerts = append(g.oldVerts, indices[i-1])
-			old = append(old, i-1)
-			g.oldVerts = append(g.oldVerts, idx)
-			old = append(old, i)
-		}
-		polyIdx++
-		if indices[i] < 0 {
-			polyIdx = 0
-		}
-	}
-	return old
-}
-
 func parseGeometry(scene *Scene, element *Element) (*Geometry, error) {
 	if element.Properties == nil {
 		return nil, errors.New("Geometry invalid")
@@ -252,20 +213,7 @@ func parseGeometry(scene *Scene, element *Element) (*Geometry, error) {
 		}
 	}
 
-	toOldIndices := geom.triangulate(origIndices)
-	geom.Vertices = make([]floatgeom.Point3, len(geom.oldVerts))
-
-	for i, vIdx := range geom.oldVerts {
-		v := vertices[vIdx]
-		geom.Vertices[i] = v
-	}
-
-	geom.newVerts = make([]Vertex, len(geom.Vertices))
-
-	for i := 0; i < len(geom.oldVerts); i++ {
-		old := geom.oldVerts[i]
-		geom.newVerts[old].add(i)
-	}
+	geom.Vertices = vertices
 
 	layerMaterialElements := findChildren(element, "LayerElementMaterial")
 	if len(layerMaterialElements) > 0 {
@@ -274,41 +222,32 @@ func parseGeometry(scene *Scene, element *Element) (*Geometry, error) {
 		if len(mappingProp) == 0 || len(referenceProp) == 0 {
 			return nil, errors.New("Invalid LayerElementMaterial")
 		}
-		var err error
-		tmp := make([]int, 0)
-
-		if mappingProp[0].value.String() == "ByPolygon" &&
-			referenceProp[0].value.String() == "IndexToDirect" {
-			geom.Materials = make([]int, len(geom.Vertices)/3)
-			for i := 0; i < len(geom.Vertices)/3; i++ {
-				geom.Materials[i] = -1
-			}
 
-			indiciesProp := findChildProperty(layerMaterialElements[0], "Materials")
-			if indiciesProp == nil {
-				return nil, errors.New("Invalid LayerElementMaterial")
-			}
+		indiciesProp := findChildProperty(layerMaterialElements[0], "Materials")
+		if indiciesProp == nil {
+			return nil, errors.New("Invalid LayerElementMaterial")
+		}
+		tmp, err := parseBinaryArrayInt(indiciesProp[0])
+		if err != nil {
+			return nil, err
+		}
 
-			tmp, err = parseBinaryArrayInt(indiciesProp[0])
-			if err != nil {
-				return nil, err
+		mapping := mappingProp[0].value.String()
+		if mapping == "ByPolygon" &&
+			referenceProp[0].value.String() == "IndexToDirect" {
+			if len(tmp) != len(geom.Faces) {
+				return nil, errors.New("Invalid LayerElementMaterial: expected one material per face")
 			}
-
-			tmpI := 0
-			triCt := 0
-			insertIdx := 0
-			for poly := 0; poly < len(tmp); {
-				poly++
-				triCt, tmpI = getTriCountFromPoly(origIndices, tmpI)
-				for i := 0; i < triCt; i++ {
-					geom.Materials[insertIdx] = tmp[poly]
-					insertIdx++
+			geom.Materials = tmp
+		} else if mapping == "AllSame" {
+			geom.Materials = make([]int, len(geom.Faces))
+			if len(tmp) > 0 {
+				for i := range geom.Materials {
+					geom.Materials[i] = tmp[0]
 				}
 			}
 		} else {
-			if mappingProp[0].value.String() != "AllSame" {
-				return nil, errors.New("Mapping not supported")
-			}
+			return nil, errors.New("Mapping not supported")
 		}
 	}
 
@@ -325,16 +264,21 @@ func parseGeometry(scene *Scene, element *Element) (*Geometry, error) {
 			if err != nil {
 				return nil, err
 			}
+			if name := findSingleChildProperty(elem, "Name"); name != nil {
+				geom.UVNames[uvIdx] = name.value.String()
+			}
 			if tmp != nil && len(tmp) > 0 {
 				//uvs = [4]floatgeom.Point2{} //resize(tmpIndices.empty() ? tmp.size() : tmpIndices.size());
 				geom.UVs[uvIdx] = splatVec2(mapping, tmp, tmpIndices, origIndices)
-				remapVec2(&geom.UVs[uvIdx], toOldIndices)
 			}
 		}
 
 	}
 
-	layerTangentElems := findChildren(element, "LayerElementTangents")
+	layerTangentElems := findChildren(element, "LayerElementTangent")
+	if len(layerTangentElems) == 0 {
+		layerTangentElems = findChildren(element, "LayerElementTangents")
+	}
 	if len(layerTangentElems) > 0 {
 		tans := findChildren(layerTangentElems[0], "Tangents")
 		var tmp []floatgeom.Point3
@@ -351,7 +295,17 @@ func parseGeometry(scene *Scene, element *Element) (*Geometry, error) {
 		}
 		if tmp != nil && len(tmp) > 0 {
 			geom.Tangents = splatVec3(mapping, tmp, tmpIndices, origIndices)
-			remapVec3(&geom.Tangents, toOldIndices)
+		}
+	}
+
+	layerBinormalElems := findChildren(element, "LayerElementBinormal")
+	if len(layerBinormalElems) > 0 {
+		tmp, tmpIndices, mapping, err := parseVertexDataVec3(layerBinormalElems[0], "Binormals", "BinormalsIndex")
+		if err != nil {
+			return nil, err
+		}
+		if len(tmp) > 0 {
+			geom.Binormals = splatVec3(mapping, tmp, tmpIndices, origIndices)
 		}
 	}
 
@@ -363,7 +317,6 @@ func parseGeometry(scene *Scene, element *Element) (*Geometry, error) {
 		}
 		if len(tmp) > 0 {
 			geom.Colors = splatVec4(mapping, tmp, tmpIndices, origIndices)
-			remapVec4(&geom.Colors, toOldIndices)
 		}
 	}
 
@@ -375,13 +328,8 @@ func parseGeometry(scene *Scene, element *Element) (*Geometry, error) {
 		}
 		if len(tmp) > 0 {
 			geom.Normals = splatVec3(mapping, tmp, tmpIndices, origIndices)
-			remapVec3(&geom.Normals, toOldIndices)
 		}
 	}
 
-	// Todo: undo / redo some work above to not require redoing vertices
-
-	geom.Vertices = vertices
-
 	return geom, nil
 }
@@ -0,0 +1,653 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oakmound/ofbx"
+	"github.com/pkg/errors"
+)
+
+// DefaultBufferURI is the uri WriteGLTF gives the binary buffer when Options.BufferURI is unset
+const DefaultBufferURI = "scene.bin"
+
+// DefaultSampleRate is the number of animation samples per second used
+// when neither Options.SampleRate nor the scene's frame rate are set
+const DefaultSampleRate = 30
+
+// Options control how a scene is exported
+type Options struct {
+	// BufferURI is the uri WriteGLTF references the binary buffer by,
+	// i.e. where the data written to bin should be saved relative to the JSON
+	BufferURI string
+	// SampleRate is the number of animation samples taken per second,
+	// defaulting to the scene's frame rate
+	SampleRate float64
+}
+
+// Export converts a scene into a glTF document and the contents of its single buffer.
+// The buffer has no uri; WriteGLTF and WriteGLB set it up for their formats.
+func Export(scene *ofbx.Scene, opts Options) (*Document, []byte, error) {
+	if scene == nil {
+		return nil, nil, errors.New("No scene to export")
+	}
+	e := &exporter{
+		scene:     scene,
+		opts:      opts,
+		doc:       &Document{Asset: Asset{Version: "2.0", Generator: "ofbx"}},
+		nodes:     make(map[uint64]int),
+		materials: make(map[materialKey]int),
+		textures:  make(map[uint64]int),
+	}
+	objs := e.exportNodes()
+	for _, obj := range objs {
+		mesh, ok := obj.(*ofbx.Mesh)
+		if !ok || mesh.Geometry == nil {
+			continue
+		}
+		if err := e.exportMesh(mesh); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, stack := range scene.AnimationStacks {
+		e.exportAnimation(stack)
+	}
+	if e.bin.Len() != 0 {
+		e.doc.Buffers = []Buffer{{ByteLength: e.bin.Len()}}
+	}
+	return e.doc, e.bin.Bytes(), nil
+}
+
+// WriteGLTF writes the scene as glTF JSON to w and its binary buffer to bin
+func WriteGLTF(w, bin io.Writer, scene *ofbx.Scene, opts Options) error {
+	doc, data, err := Export(scene, opts)
+	if err != nil {
+		return err
+	}
+	if len(doc.Buffers) != 0 {
+		doc.Buffers[0].URI = opts.BufferURI
+		if doc.Buffers[0].URI == "" {
+			doc.Buffers[0].URI = DefaultBufferURI
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return errors.Wrap(err, "Encoding glTF failed")
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if bin == nil {
+		return errors.New("No writer for the binary buffer")
+	}
+	_, err = bin.Write(data)
+	return err
+}
+
+const (
+	glbMagic     = 0x46546C67 // glTF
+	glbJSONChunk = 0x4E4F534A // JSON
+	glbBINChunk  = 0x004E4942 // BIN
+)
+
+// WriteGLB writes the scene as a single binary glTF file
+func WriteGLB(w io.Writer, scene *ofbx.Scene, opts Options) error {
+	doc, data, err := Export(scene, opts)
+	if err != nil {
+		return err
+	}
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Wrap(err, "Encoding glTF failed")
+	}
+	// Chunks are 4 byte aligned, JSON with spaces and binary data with zeros
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+
+	length := 12 + 8 + len(js)
+	if len(data) != 0 {
+		length += 8 + len(data)
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, []uint32{glbMagic, 2, uint32(length)})
+	binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(js)), glbJSONChunk})
+	buf.Write(js)
+	if len(data) != 0 {
+		binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(data)), glbBINChunk})
+		buf.Write(data)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+type exporter struct {
+	scene *ofbx.Scene
+	opts  Options
+	doc   *Document
+	bin   bytes.Buffer
+
+	// nodes and textures map ofbx ids to document indices
+	nodes     map[uint64]int
+	materials map[materialKey]int
+	textures  map[uint64]int
+}
+
+// materialKey identifies an exported material. A material is exported once for each
+// set of texCoords its textures are sampled with, as meshes may order their UV sets
+// differently.
+type materialKey struct {
+	id        uint64
+	texCoords [ofbx.TextureCOUNT]int
+}
+
+// exportNodes adds every Model in the scene, ordered by id, and
+// links them into the scene's hierarchy
+func (e *exporter) exportNodes() []ofbx.Obj {
+	objs := []ofbx.Obj{}
+	for _, obj := range e.scene.ObjectMap {
+		if obj != nil && obj.IsNode() && obj.Type() != ofbx.ROOT {
+			objs = append(objs, obj)
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		return objs[i].ID() < objs[j].ID()
+	})
+
+	for i, obj := range objs {
+		e.nodes[obj.ID()] = i
+		node := Node{Name: ofbx.BaseName(obj.Name())}
+		t, r, s := decompose(obj.LocalTransform())
+		if t != ([3]float64{}) {
+			node.Translation = &t
+		}
+		if r != ([4]float64{0, 0, 0, 1}) {
+			node.Rotation = &r
+		}
+		if s != ([3]float64{1, 1, 1}) {
+			node.Scale = &s
+		}
+		e.doc.Nodes = append(e.doc.Nodes, node)
+	}
+
+	roots := []int{}
+	for i, obj := range objs {
+		for _, c := range obj.Children() {
+			if child, ok := e.nodes[c.ID()]; ok {
+				e.doc.Nodes[i].Children = append(e.doc.Nodes[i].Children, child)
+			}
+		}
+		if parent := obj.Parent(); parent == nil || parent.Type() == ofbx.ROOT {
+			roots = append(roots, i)
+		}
+	}
+	e.doc.Scenes = []Scene{{Nodes: roots}}
+	e.doc.Scene = intPtr(0)
+	return objs
+}
+
+// exportMesh adds a mesh, and its skin, to the mesh's node. Each polygon vertex becomes
+// a glTF vertex, as that is how the geometry's attributes are stored, and polygons are
+// triangulated as fans. Faces are grouped into one primitive per material.
+func (e *exporter) exportMesh(mesh *ofbx.Mesh) error {
+	geom := mesh.Geometry
+	node := e.nodes[mesh.ID()]
+
+	count := 0
+	for _, face := range geom.Faces {
+		count += len(face)
+	}
+	var uvSets []int
+	for i, uvs := range geom.UVs {
+		if len(uvs) == count {
+			uvSets = append(uvSets, i)
+		}
+	}
+	hasNormals := len(geom.Normals) == count
+	hasTangents := len(geom.Tangents) == count
+	hasBinormals := hasNormals && hasTangents && len(geom.Binormals) == count
+	hasColors := len(geom.Colors) == count
+	skin, influences := e.exportSkin(geom)
+	// glTF has no geometric transforms, so they are applied to the vertices
+	geometric := mesh.GeometricTransform()
+	normalTransform, _ := geometric.Inverse()
+	normalTransform = normalTransform.Transpose()
+	// A mirroring transform flips which way binormals point
+	mirrored := geometric.Determinant() < 0
+
+	positions := make([]float32, 0, count*3)
+	var normals, tangents, colors []float32
+	uvs := make([][]float32, len(uvSets))
+	var joints []uint16
+	var weights []float32
+
+	indices := map[int][]uint32{}
+	materialOrder := []int{}
+	pv := 0
+	for fi, face := range geom.Faces {
+		for k, ci := range face {
+			if ci < 0 || ci >= len(geom.Vertices) {
+				return errors.New("Face index out of range in mesh " + mesh.Name())
+			}
+			v := geom.Vertices[ci]
+			v = geometric.TransformPoint(v)
+			positions = append(positions, float32(v.X()), float32(v.Y()), float32(v.Z()))
+			if hasNormals {
+				n := normalTransform.TransformDirection(geom.Normals[pv+k]).Normalize()
+				normals = append(normals, float32(n.X()), float32(n.Y()), float32(n.Z()))
+			}
+			if hasTangents {
+				t := geometric.TransformDirection(geom.Tangents[pv+k]).Normalize()
+				// glTF stores the binormal's side of the normal and tangent as the tangent's w
+				var w float32 = 1
+				if hasBinormals {
+					n, t := geom.Normals[pv+k], geom.Tangents[pv+k]
+					if n.Cross(t).Dot(geom.Binormals[pv+k]) < 0 {
+						w = -1
+					}
+				}
+				if mirrored {
+					w = -w
+				}
+				tangents = append(tangents, float32(t.X()), float32(t.Y()), float32(t.Z()), w)
+			}
+			for i, set := range uvSets {
+				uv := geom.UVs[set][pv+k]
+				// FBX's v axis points up, glTF's points down
+				uvs[i] = append(uvs[i], float32(uv.X()), float32(1-uv.Y()))
+			}
+			if hasColors {
+				c := geom.Colors[pv+k]
+				colors = append(colors, float32(c.X()), float32(c.Y()), float32(c.Z()), float32(c.W()))
+			}
+			if influences != nil {
+				joints = append(joints, influences[ci].joints[:]...)
+				weights = append(weights, influences[ci].weights[:]...)
+			}
+		}
+		if len(face) >= 3 {
+			mat := 0
+			if fi < len(geom.Materials) {
+				mat = geom.Materials[fi]
+			}
+			if _, ok := indices[mat]; !ok {
+				materialOrder = append(materialOrder, mat)
+			}
+			for k := 1; k+1 < len(face); k++ {
+				indices[mat] = append(indices[mat], uint32(pv), uint32(pv+k), uint32(pv+k+1))
+			}
+		}
+		pv += len(face)
+	}
+
+	attributes := map[string]int{
+		"POSITION": e.addFloats(positions, 3, ArrayBuffer, true),
+	}
+	if hasNormals {
+		attributes["NORMAL"] = e.addFloats(normals, 3, ArrayBuffer, false)
+	}
+	if hasTangents {
+		attributes["TANGENT"] = e.addFloats(tangents, 4, ArrayBuffer, false)
+	}
+	for i, set := range uvs {
+		attributes["TEXCOORD_"+strconv.Itoa(i)] = e.addFloats(set, 2, ArrayBuffer, false)
+	}
+	if hasColors {
+		attributes["COLOR_0"] = e.addFloats(colors, 4, ArrayBuffer, false)
+	}
+	if influences != nil {
+		attributes["JOINTS_0"] = e.addJoints(joints)
+		attributes["WEIGHTS_0"] = e.addFloats(weights, 4, ArrayBuffer, false)
+	}
+
+	gm := Mesh{Name: ofbx.BaseName(mesh.Name())}
+	for _, mat := range materialOrder {
+		prim := Primitive{
+			Attributes: attributes,
+			Indices:    intPtr(e.addIndices(indices[mat])),
+		}
+		if mat >= 0 && mat < len(mesh.Materials) && mesh.Materials[mat] != nil {
+			prim.Material = intPtr(e.exportMaterial(mesh.Materials[mat], geom, uvSets))
+		}
+		gm.Primitives = append(gm.Primitives, prim)
+	}
+	if len(gm.Primitives) == 0 {
+		return nil
+	}
+	e.doc.Meshes = append(e.doc.Meshes, gm)
+	e.doc.Nodes[node].Mesh = intPtr(len(e.doc.Meshes) - 1)
+	if influences != nil {
+		e.doc.Nodes[node].Skin = intPtr(skin)
+	}
+	return nil
+}
+
+// influence is the four strongest joints acting on a control point
+type influence struct {
+	joints  [4]uint16
+	weights [4]float32
+}
+
+// exportSkin adds the geometry's skin, returning its index and the influences
+// on each control point, or nil if the geometry has no skin
+func (e *exporter) exportSkin(geom *ofbx.Geometry) (int, []influence) {
+	if geom.Skin == nil {
+		return 0, nil
+	}
+	skin := Skin{Name: ofbx.BaseName(geom.Skin.Name())}
+	// joints maps cluster indices to joint indices
+	joints := make(map[int]int)
+	var inverseBinds []float32
+	for ci, cluster := range geom.Skin.Clusters {
+		if cluster.Link == nil {
+			continue
+		}
+		node, ok := e.nodes[cluster.Link.ID()]
+		if !ok {
+			continue
+		}
+		joints[ci] = len(skin.Joints)
+		skin.Joints = append(skin.Joints, node)
+		// Mesh space at bind time to the joint's space at bind time
+		linkInverse, ok := cluster.TransformLink.Inverse()
+		if !ok {
+			// A singular bind matrix has no inverse, so the joint is bound at the origin
+			linkInverse = ofbx.IdentityMatrix()
+		}
+		bind := linkInverse.Mul(cluster.Transform)
+		for _, f := range bind.Elements() {
+			inverseBinds = append(inverseBinds, float32(f))
+		}
+	}
+	if len(skin.Joints) == 0 {
+		return 0, nil
+	}
+	skin.InverseBindMatrices = intPtr(e.addFloats(inverseBinds, 16, 0, false))
+	e.doc.Skins = append(e.doc.Skins, skin)
+
+	vertexInfluences := geom.Skin.VertexInfluences(4)
+	influences := make([]influence, len(geom.Vertices))
+	for v := range influences {
+		if v >= len(vertexInfluences.Clusters) {
+			influences[v].weights[0] = 1
+			continue
+		}
+		total := 0.0
+		for i, ci := range vertexInfluences.Clusters[v] {
+			if joint, ok := joints[ci]; ok && vertexInfluences.Weights[v][i] > 0 {
+				influences[v].joints[i] = uint16(joint)
+				influences[v].weights[i] = float32(vertexInfluences.Weights[v][i])
+				total += vertexInfluences.Weights[v][i]
+			}
+		}
+		if total == 0 {
+			// glTF requires weights to sum to one
+			influences[v] = influence{}
+			influences[v].weights[0] = 1
+			continue
+		}
+		// Renormalize if any influence was on a node which isn't exported
+		for i := range influences[v].weights {
+			influences[v].weights[i] /= float32(total)
+		}
+	}
+	return len(e.doc.Skins) - 1, influences
+}
+
+// exportMaterial adds the material as it is used by geom, whose UVs in uvSets are
+// exported as TEXCOORD_0, TEXCOORD_1 and so on
+func (e *exporter) exportMaterial(mat *ofbx.Material, geom *ofbx.Geometry, uvSets []int) int {
+	key := materialKey{id: mat.ID()}
+	for slot, tex := range mat.Textures {
+		if tex == nil {
+			continue
+		}
+		channel := tex.UVChannel(geom)
+		for i, set := range uvSets {
+			if set == channel {
+				key.texCoords[slot] = i
+			}
+		}
+	}
+	if idx, ok := e.materials[key]; ok {
+		return idx
+	}
+	textureInfo := func(slot ofbx.TextureType) *TextureInfo {
+		if tex, ok := e.exportTexture(mat.Textures[slot]); ok {
+			return &TextureInfo{Index: tex, TexCoord: key.texCoords[slot]}
+		}
+		return nil
+	}
+	// Blinn-Phong's specular exponent mapped to a roughness
+	roughness := math.Sqrt(2 / (mat.ShininessExponent + 2))
+	if math.IsNaN(roughness) || roughness > 1 {
+		roughness = 1
+	}
+	pbr := &PBRMetallicRoughness{
+		BaseColorFactor: &[4]float64{float64(mat.DiffuseColor.R), float64(mat.DiffuseColor.G), float64(mat.DiffuseColor.B), 1},
+		RoughnessFactor: roughness,
+	}
+	pbr.BaseColorTexture = textureInfo(ofbx.DIFFUSE)
+	gm := Material{
+		Name:                 ofbx.BaseName(mat.Name()),
+		PBRMetallicRoughness: pbr,
+		NormalTexture:        textureInfo(ofbx.NORMAL),
+	}
+	if mat.EmissiveFactor != 0 {
+		c := mat.EmissiveColor
+		gm.EmissiveFactor = &[3]float64{
+			float64(c.R) * mat.EmissiveFactor,
+			float64(c.G) * mat.EmissiveFactor,
+			float64(c.B) * mat.EmissiveFactor,
+		}
+	}
+	if gm.EmissiveTexture = textureInfo(ofbx.EMISSIVE); gm.EmissiveTexture != nil {
+		// The texture is multiplied by the factor, which otherwise defaults to black
+		if gm.EmissiveFactor == nil {
+			gm.EmissiveFactor = &[3]float64{1, 1, 1}
+		}
+	}
+	e.doc.Materials = append(e.doc.Materials, gm)
+	e.materials[key] = len(e.doc.Materials) - 1
+	return len(e.doc.Materials) - 1
+}
+
+// exportTexture adds a texture referring to its file, preferring the relative path
+func (e *exporter) exportTexture(tex *ofbx.Texture) (int, bool) {
+	if tex == nil {
+		return 0, false
+	}
+	if idx, ok := e.textures[tex.ID()]; ok {
+		return idx, true
+	}
+	uri := tex.RelativeFilename
+	if uri == "" {
+		uri = tex.Filename
+	}
+	if uri == "" {
+		return 0, false
+	}
+	uri = strings.Replace(uri, "\\", "/", -1)
+	e.doc.Images = append(e.doc.Images, Image{URI: uri})
+	e.doc.Textures = append(e.doc.Textures, Texture{
+		Name:    ofbx.BaseName(tex.Name()),
+		Sampler: intPtr(e.exportSampler(tex)),
+		Source:  intPtr(len(e.doc.Images) - 1),
+	})
+	e.textures[tex.ID()] = len(e.doc.Textures) - 1
+	return len(e.doc.Textures) - 1, true
+}
+
+// exportSampler returns the index of a sampler with the texture's wrap modes and
+// filtering, adding it if no earlier texture used the same one
+func (e *exporter) exportSampler(tex *ofbx.Texture) int {
+	wrap := func(mode ofbx.WrapMode) int {
+		if mode == ofbx.WrapClamp {
+			return WrapClampToEdge
+		}
+		return WrapRepeat
+	}
+	sampler := Sampler{
+		MagFilter: FilterLinear,
+		MinFilter: FilterLinear,
+		WrapS:     wrap(tex.WrapModeU),
+		WrapT:     wrap(tex.WrapModeV),
+	}
+	if tex.UseMipMap {
+		sampler.MinFilter = FilterLinearMipmapLinear
+	}
+	for i, s := range e.doc.Samplers {
+		if s == sampler {
+			return i
+		}
+	}
+	e.doc.Samplers = append(e.doc.Samplers, sampler)
+	return len(e.doc.Samplers) - 1
+}
+
+// exportAnimation bakes every animated node of the stack. Each animated node gets
+// translation, rotation and scale channels, as pivots and offsets can make any one
+// FBX property affect all three.
+func (e *exporter) exportAnimation(stack *ofbx.AnimationStack) {
+	rate := e.opts.SampleRate
+	if rate <= 0 {
+		rate = float64(e.scene.FrameRate)
+	}
+	if rate <= 0 {
+		rate = DefaultSampleRate
+	}
+	baked := stack.Bake(rate, ofbx.BakeOptions{})
+	tracks := []*ofbx.Track{}
+	for _, track := range baked.Tracks {
+		if _, ok := e.nodes[track.Node.ID()]; ok {
+			tracks = append(tracks, track)
+		}
+	}
+	if len(tracks) == 0 {
+		return
+	}
+
+	seconds := make([]float32, len(baked.Times))
+	for i, t := range baked.Times {
+		seconds[i] = float32((t - baked.Times[0]).Seconds())
+	}
+
+	anim := Animation{Name: ofbx.BaseName(stack.Name())}
+	input := e.addFloats(seconds, 1, 0, true)
+	for _, track := range tracks {
+		var translations, rotations, scales []float32
+		for i := range baked.Times {
+			tr, r, s := track.Translations[i], track.Rotations[i], track.Scales[i]
+			translations = append(translations, float32(tr.X()), float32(tr.Y()), float32(tr.Z()))
+			rotations = append(rotations, float32(r.X), float32(r.Y), float32(r.Z), float32(r.W))
+			scales = append(scales, float32(s.X()), float32(s.Y()), float32(s.Z()))
+		}
+		for _, ch := range []struct {
+			path       string
+			data       []float32
+			components int
+		}{
+			{"translation", translations, 3},
+			{"rotation", rotations, 4},
+			{"scale", scales, 3},
+		} {
+			anim.Samplers = append(anim.Samplers, AnimationSampler{
+				Input:         input,
+				Output:        e.addFloats(ch.data, ch.components, 0, false),
+				Interpolation: "LINEAR",
+			})
+			anim.Channels = append(anim.Channels, Channel{
+				Sampler: len(anim.Samplers) - 1,
+				Target:  ChannelTarget{Node: e.nodes[track.Node.ID()], Path: ch.path},
+			})
+		}
+	}
+	e.doc.Animations = append(e.doc.Animations, anim)
+}
+
+// addView appends data to the buffer, aligned to 4 bytes, and returns its BufferView
+func (e *exporter) addView(data interface{}, target int) int {
+	for e.bin.Len()%4 != 0 {
+		e.bin.WriteByte(0)
+	}
+	offset := e.bin.Len()
+	binary.Write(&e.bin, binary.LittleEndian, data)
+	e.doc.BufferViews = append(e.doc.BufferViews, BufferView{
+		ByteOffset: offset,
+		ByteLength: e.bin.Len() - offset,
+		Target:     target,
+	})
+	return len(e.doc.BufferViews) - 1
+}
+
+var accessorTypes = map[int]string{
+	1:  "SCALAR",
+	2:  "VEC2",
+	3:  "VEC3",
+	4:  "VEC4",
+	16: "MAT4",
+}
+
+func (e *exporter) addFloats(data []float32, components, target int, bounds bool) int {
+	acc := Accessor{
+		BufferView:    e.addView(data, target),
+		ComponentType: Float,
+		Count:         len(data) / components,
+		Type:          accessorTypes[components],
+	}
+	if bounds && len(data) != 0 {
+		acc.Min = make([]float64, components)
+		acc.Max = make([]float64, components)
+		for i := 0; i < components; i++ {
+			acc.Min[i] = float64(data[i])
+			acc.Max[i] = float64(data[i])
+		}
+		for i, f := range data {
+			c := i % components
+			acc.Min[c] = math.Min(acc.Min[c], float64(f))
+			acc.Max[c] = math.Max(acc.Max[c], float64(f))
+		}
+	}
+	e.doc.Accessors = append(e.doc.Accessors, acc)
+	return len(e.doc.Accessors) - 1
+}
+
+func (e *exporter) addIndices(data []uint32) int {
+	e.doc.Accessors = append(e.doc.Accessors, Accessor{
+		BufferView:    e.addView(data, ElementArrayBuffer),
+		ComponentType: UnsignedInt,
+		Count:         len(data),
+		Type:          "SCALAR",
+	})
+	return len(e.doc.Accessors) - 1
+}
+
+func (e *exporter) addJoints(data []uint16) int {
+	e.doc.Accessors = append(e.doc.Accessors, Accessor{
+		BufferView:    e.addView(data, ArrayBuffer),
+		ComponentType: UnsignedShort,
+		Count:         len(data) / 4,
+		Type:          "VEC4",
+	})
+	return len(e.doc.Accessors) - 1
+}
+
+// decompose splits a matrix into glTF's translation, rotation and scale
+func decompose(m ofbx.Matrix) (t [3]float64, r [4]float64, s [3]float64) {
+	translation, rotation, scale := m.Decompose()
+	return translation, [4]float64{rotation.X, rotation.Y, rotation.Z, rotation.W}, scale
+}
+
+func intPtr(i int) *int {
+	return &i
+}
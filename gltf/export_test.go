@@ -0,0 +1,266 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oakmound/ofbx"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestScene(t *testing.T) *ofbx.Scene {
+	f, err := os.Open("../testdata/cube.fbx")
+	require.Nil(t, err)
+	defer f.Close()
+	scene, err := ofbx.Load(f)
+	require.Nil(t, err)
+	return scene
+}
+
+func TestExport(t *testing.T) {
+	doc, bin, err := Export(loadTestScene(t), Options{})
+	require.Nil(t, err)
+
+	require.Len(t, doc.Nodes, 3)
+	require.Equal(t, "Cube", doc.Nodes[0].Name)
+	require.Equal(t, &[3]float64{1.5, -2, 0.3}, doc.Nodes[0].Translation)
+	require.Equal(t, "Root", doc.Nodes[1].Name)
+	require.Equal(t, []int{2}, doc.Nodes[1].Children)
+	require.Equal(t, []int{0, 1}, doc.Scenes[0].Nodes)
+
+	require.Len(t, doc.Meshes, 1)
+	require.Len(t, doc.Meshes[0].Primitives, 1)
+	prim := doc.Meshes[0].Primitives[0]
+	// Six quads, each split into two triangles
+	require.Equal(t, 24, doc.Accessors[prim.Attributes["POSITION"]].Count)
+	require.Equal(t, 36, doc.Accessors[*prim.Indices].Count)
+	require.Equal(t, []float64{-1, -1, -1}, doc.Accessors[prim.Attributes["POSITION"]].Min)
+	for _, attr := range []string{"NORMAL", "TEXCOORD_0", "JOINTS_0", "WEIGHTS_0"} {
+		require.Contains(t, prim.Attributes, attr)
+	}
+
+	require.Len(t, doc.Materials, 1)
+	mat := doc.Materials[0]
+	require.Equal(t, "Red", mat.Name)
+	require.InDelta(t, 0.8, mat.PBRMetallicRoughness.BaseColorFactor[0], 1e-6)
+	require.NotNil(t, mat.PBRMetallicRoughness.BaseColorTexture)
+	require.Equal(t, "textures/diffuse.png", doc.Images[0].URI)
+	require.Equal(t, []Sampler{{MagFilter: FilterLinear, MinFilter: FilterLinear, WrapS: WrapRepeat, WrapT: WrapRepeat}}, doc.Samplers)
+	require.Equal(t, 0, *doc.Textures[0].Sampler)
+
+	require.Len(t, doc.Skins, 1)
+	require.Equal(t, []int{1, 2}, doc.Skins[0].Joints)
+	require.Equal(t, 0, *doc.Nodes[0].Skin)
+	ibm := accessorFloats(t, doc, bin, *doc.Skins[0].InverseBindMatrices)
+	require.Len(t, ibm, 32)
+	// The bone's bind position is 3 up and its cluster Transform moves the mesh 3 down
+	require.Equal(t, float32(-6), ibm[16+13])
+
+	require.Len(t, doc.Animations, 1)
+	anim := doc.Animations[0]
+	require.Equal(t, "Take 001", anim.Name)
+	require.Len(t, anim.Channels, 3)
+	require.Equal(t, 2, anim.Channels[1].Target.Node)
+	require.Equal(t, "rotation", anim.Channels[1].Target.Path)
+	input := doc.Accessors[anim.Samplers[1].Input]
+	// One second at 24 frames per second
+	require.Equal(t, 25, input.Count)
+	require.InDelta(t, 1, input.Max[0], 1e-3)
+	rotations := accessorFloats(t, doc, bin, anim.Samplers[1].Output)
+	last := rotations[len(rotations)-4:]
+	angle := 90.5 * math.Pi / 180
+	require.InDelta(t, math.Sin(angle/2), last[2], 1e-3)
+	require.InDelta(t, math.Cos(angle/2), last[3], 1e-3)
+}
+
+func TestExportSingularBindMatrix(t *testing.T) {
+	scene := loadTestScene(t)
+	cluster := scene.Meshes[0].Geometry.Skin.Clusters[1]
+	cluster.TransformLink = ofbx.Matrix{}
+	doc, bin, err := Export(scene, Options{})
+	require.Nil(t, err)
+	ibm := accessorFloats(t, doc, bin, *doc.Skins[0].InverseBindMatrices)
+	for i, f := range cluster.Transform.Elements() {
+		require.Equal(t, float32(f), ibm[16+i])
+	}
+}
+
+const uvSetScene = `; FBX 7.4.0 project file
+Objects:  {
+	Geometry: 200, "Geometry::Quad", "Mesh" {
+		Vertices: *9 {
+			a: 0,0,0,1,0,0,1,1,0
+		} 
+		PolygonVertexIndex: *3 {
+			a: 0,1,-3
+		} 
+		LayerElementUV: 0 {
+			Name: "map1"
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			UV: *6 {
+				a: 0,0,1,0,1,1
+			} 
+		}
+		LayerElementUV: 1 {
+			Name: "detail"
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			UV: *6 {
+				a: 0,0,0.5,0,0.5,0.5
+			} 
+		}
+	}
+	Model: 100, "Model::Quad", "Mesh" {
+	}
+	Material: 600, "Material::Tiled", "" {
+	}
+	Texture: 700, "Texture::Detail", "" {
+		Properties70:  {
+			P: "UVSet", "KString", "", "", "detail"
+		}
+		RelativeFilename: "detail.png"
+	}
+	Texture: 701, "Texture::Normal", "" {
+		RelativeFilename: "normal.png"
+	}
+}
+Connections:  {
+	C: "OO",100,0
+	C: "OO",200,100
+	C: "OO",600,100
+	C: "OP",700,600, "DiffuseColor"
+	C: "OP",701,600, "NormalMap"
+}
+`
+
+func TestExportTexCoords(t *testing.T) {
+	scene, err := ofbx.Load(strings.NewReader(uvSetScene))
+	require.Nil(t, err)
+	doc, _, err := Export(scene, Options{})
+	require.Nil(t, err)
+	require.Contains(t, doc.Meshes[0].Primitives[0].Attributes, "TEXCOORD_1")
+	mat := doc.Materials[0]
+	require.Equal(t, 1, mat.PBRMetallicRoughness.BaseColorTexture.TexCoord)
+	require.Equal(t, 0, mat.NormalTexture.TexCoord)
+}
+
+const mirroredScene = `; FBX 7.4.0 project file
+Objects:  {
+	Geometry: 200, "Geometry::Tri", "Mesh" {
+		Vertices: *9 {
+			a: 0,0,0,1,0,0,1,1,0
+		} 
+		PolygonVertexIndex: *3 {
+			a: 0,1,-3
+		} 
+		LayerElementNormal: 0 {
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			Normals: *9 {
+				a: 1,0,1,1,0,1,1,0,1
+			} 
+		}
+		LayerElementTangent: 0 {
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			Tangents: *9 {
+				a: 1,0,0,1,0,0,1,0,0
+			} 
+		}
+		LayerElementBinormal: 0 {
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			Binormals: *9 {
+				a: 0,-1,0,0,-1,0,0,-1,0
+			} 
+		}
+	}
+	Model: 100, "Model::Tri", "Mesh" {
+		Properties70:  {
+			P: "GeometricScaling", "Vector3D", "Vector", "",2,1,-1
+		}
+	}
+}
+Connections:  {
+	C: "OO",100,0
+	C: "OO",200,100
+}
+`
+
+func TestExportNormalsAndTangents(t *testing.T) {
+	scene, err := ofbx.Load(strings.NewReader(mirroredScene))
+	require.Nil(t, err)
+	doc, bin, err := Export(scene, Options{})
+	require.Nil(t, err)
+	attributes := doc.Meshes[0].Primitives[0].Attributes
+
+	// Normals are scaled by the inverse of the geometric scaling
+	normals := accessorFloats(t, doc, bin, attributes["NORMAL"])
+	want := [3]float64{0.5, 0, -1}
+	length := math.Sqrt(want[0]*want[0] + want[2]*want[2])
+	for i, f := range want {
+		require.InDelta(t, f/length, normals[i], 1e-6)
+	}
+
+	// The binormals are on the -w side of the tangents, which mirroring flips
+	tangents := accessorFloats(t, doc, bin, attributes["TANGENT"])
+	require.Equal(t, []float32{1, 0, 0, 1}, tangents[:4])
+
+	scene.Meshes[0].Geometry.Binormals[0] = scene.Meshes[0].Geometry.Binormals[0].MulConst(-1)
+	doc, bin, err = Export(scene, Options{})
+	require.Nil(t, err)
+	tangents = accessorFloats(t, doc, bin, doc.Meshes[0].Primitives[0].Attributes["TANGENT"])
+	require.Equal(t, []float32{1, 0, 0, -1}, tangents[:4])
+}
+
+func accessorFloats(t *testing.T, doc *Document, bin []byte, idx int) []float32 {
+	acc := doc.Accessors[idx]
+	require.Equal(t, Float, acc.ComponentType)
+	view := doc.BufferViews[acc.BufferView]
+	out := make([]float32, view.ByteLength/4)
+	require.Nil(t, binary.Read(bytes.NewReader(bin[view.ByteOffset:view.ByteOffset+view.ByteLength]), binary.LittleEndian, out))
+	return out
+}
+
+func TestWriteGLTF(t *testing.T) {
+	js := &bytes.Buffer{}
+	bin := &bytes.Buffer{}
+	require.Nil(t, WriteGLTF(js, bin, loadTestScene(t), Options{BufferURI: "cube.bin"}))
+
+	doc := &Document{}
+	require.Nil(t, json.Unmarshal(js.Bytes(), doc))
+	require.Equal(t, "2.0", doc.Asset.Version)
+	require.Len(t, doc.Buffers, 1)
+	require.Equal(t, "cube.bin", doc.Buffers[0].URI)
+	require.Equal(t, bin.Len(), doc.Buffers[0].ByteLength)
+	for _, view := range doc.BufferViews {
+		require.Equal(t, 0, view.ByteOffset%4)
+	}
+}
+
+func TestWriteGLB(t *testing.T) {
+	buff := &bytes.Buffer{}
+	require.Nil(t, WriteGLB(buff, loadTestScene(t), Options{SampleRate: 10}))
+	data := buff.Bytes()
+
+	var header [5]uint32
+	require.Nil(t, binary.Read(bytes.NewReader(data), binary.LittleEndian, &header))
+	require.Equal(t, uint32(glbMagic), header[0])
+	require.Equal(t, uint32(2), header[1])
+	require.Equal(t, uint32(len(data)), header[2])
+	require.Equal(t, uint32(glbJSONChunk), header[4])
+
+	doc := &Document{}
+	require.Nil(t, json.Unmarshal(data[20:20+header[3]], doc))
+	require.Empty(t, doc.Buffers[0].URI)
+	require.Equal(t, 11, doc.Accessors[doc.Animations[0].Samplers[0].Input].Count)
+	binHeader := data[20+header[3]:]
+	require.Equal(t, uint32(glbBINChunk), binary.LittleEndian.Uint32(binHeader[4:]))
+	require.Equal(t, uint32(len(binHeader)-8), binary.LittleEndian.Uint32(binHeader))
+}
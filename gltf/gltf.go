@@ -0,0 +1,181 @@
+// Package gltf converts scenes loaded by ofbx into glTF 2.0 documents.
+// https://registry.khronos.org/glTF/specs/2.0/glTF-2.0.html
+package gltf
+
+// Document is the JSON root of a glTF asset
+type Document struct {
+	Asset       Asset        `json:"asset"`
+	Scene       *int         `json:"scene,omitempty"`
+	Scenes      []Scene      `json:"scenes,omitempty"`
+	Nodes       []Node       `json:"nodes,omitempty"`
+	Meshes      []Mesh       `json:"meshes,omitempty"`
+	Skins       []Skin       `json:"skins,omitempty"`
+	Materials   []Material   `json:"materials,omitempty"`
+	Textures    []Texture    `json:"textures,omitempty"`
+	Images      []Image      `json:"images,omitempty"`
+	Samplers    []Sampler    `json:"samplers,omitempty"`
+	Animations  []Animation  `json:"animations,omitempty"`
+	Accessors   []Accessor   `json:"accessors,omitempty"`
+	BufferViews []BufferView `json:"bufferViews,omitempty"`
+	Buffers     []Buffer     `json:"buffers,omitempty"`
+}
+
+// Asset holds the document's version and generator
+type Asset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+// Scene is a set of root nodes
+type Scene struct {
+	Name  string `json:"name,omitempty"`
+	Nodes []int  `json:"nodes,omitempty"`
+}
+
+// Node is an element of the node hierarchy
+type Node struct {
+	Name        string      `json:"name,omitempty"`
+	Children    []int       `json:"children,omitempty"`
+	Mesh        *int        `json:"mesh,omitempty"`
+	Skin        *int        `json:"skin,omitempty"`
+	Translation *[3]float64 `json:"translation,omitempty"`
+	Rotation    *[4]float64 `json:"rotation,omitempty"`
+	Scale       *[3]float64 `json:"scale,omitempty"`
+}
+
+// Mesh is a set of primitives drawn together
+type Mesh struct {
+	Name       string      `json:"name,omitempty"`
+	Primitives []Primitive `json:"primitives"`
+}
+
+// Primitive is a triangle list with a single material
+type Primitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   *int           `json:"material,omitempty"`
+}
+
+// Skin lists the joints, and their inverse bind matrices, that deform a mesh
+type Skin struct {
+	Name                string `json:"name,omitempty"`
+	InverseBindMatrices *int   `json:"inverseBindMatrices,omitempty"`
+	Joints              []int  `json:"joints"`
+}
+
+// Material is a metallic roughness material
+type Material struct {
+	Name                 string                `json:"name,omitempty"`
+	PBRMetallicRoughness *PBRMetallicRoughness `json:"pbrMetallicRoughness,omitempty"`
+	NormalTexture        *TextureInfo          `json:"normalTexture,omitempty"`
+	EmissiveTexture      *TextureInfo          `json:"emissiveTexture,omitempty"`
+	EmissiveFactor       *[3]float64           `json:"emissiveFactor,omitempty"`
+}
+
+// PBRMetallicRoughness holds a Material's base color, metallic and roughness values
+type PBRMetallicRoughness struct {
+	BaseColorFactor  *[4]float64  `json:"baseColorFactor,omitempty"`
+	BaseColorTexture *TextureInfo `json:"baseColorTexture,omitempty"`
+	MetallicFactor   float64      `json:"metallicFactor"`
+	RoughnessFactor  float64      `json:"roughnessFactor"`
+}
+
+// TextureInfo references a Texture from a Material, and the TEXCOORD attribute it is
+// sampled with
+type TextureInfo struct {
+	Index    int `json:"index"`
+	TexCoord int `json:"texCoord,omitempty"`
+}
+
+// Texture references the Image it samples and the Sampler it is sampled with
+type Texture struct {
+	Name    string `json:"name,omitempty"`
+	Sampler *int   `json:"sampler,omitempty"`
+	Source  *int   `json:"source,omitempty"`
+}
+
+// Sampler filter and wrap modes
+const (
+	FilterLinear             = 9729
+	FilterLinearMipmapLinear = 9987
+	WrapClampToEdge          = 33071
+	WrapRepeat               = 10497
+)
+
+// Sampler is how a Texture is filtered and wrapped
+type Sampler struct {
+	MagFilter int `json:"magFilter,omitempty"`
+	MinFilter int `json:"minFilter,omitempty"`
+	WrapS     int `json:"wrapS,omitempty"`
+	WrapT     int `json:"wrapT,omitempty"`
+}
+
+// Image is a texture file
+type Image struct {
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// Animation is a set of channels played together
+type Animation struct {
+	Name     string             `json:"name,omitempty"`
+	Channels []Channel          `json:"channels"`
+	Samplers []AnimationSampler `json:"samplers"`
+}
+
+// Channel targets a node's translation, rotation or scale with a sampler
+type Channel struct {
+	Sampler int           `json:"sampler"`
+	Target  ChannelTarget `json:"target"`
+}
+
+// ChannelTarget is the node and path a Channel animates
+type ChannelTarget struct {
+	Node int    `json:"node"`
+	Path string `json:"path"`
+}
+
+// AnimationSampler pairs key times with values
+type AnimationSampler struct {
+	Input         int    `json:"input"`
+	Output        int    `json:"output"`
+	Interpolation string `json:"interpolation,omitempty"`
+}
+
+// Accessor describes typed data in a BufferView
+type Accessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+// BufferView is a slice of a Buffer
+type BufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+// Buffer is binary data, either external or the GLB's binary chunk
+type Buffer struct {
+	URI        string `json:"uri,omitempty"`
+	ByteLength int    `json:"byteLength"`
+}
+
+// Accessor component types
+const (
+	UnsignedShort = 5123
+	UnsignedInt   = 5125
+	Float         = 5126
+)
+
+// BufferView targets
+const (
+	ArrayBuffer        = 34962
+	ElementArrayBuffer = 34963
+)
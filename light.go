@@ -0,0 +1,149 @@
+package ofbx
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// LightType is the kind of light a Light casts
+type LightType int
+
+// Light type values
+const (
+	LightPoint       LightType = iota
+	LightDirectional LightType = iota
+	LightSpot        LightType = iota
+	LightArea        LightType = iota
+	LightVolume      LightType = iota
+)
+
+// DecayType is how a Light's intensity falls off with distance
+type DecayType int
+
+// Decay type values
+const (
+	DecayNone      DecayType = iota
+	DecayLinear    DecayType = iota
+	DecayQuadratic DecayType = iota
+	DecayCubic     DecayType = iota
+)
+
+// AreaLightShape is the shape of an area Light
+type AreaLightShape int
+
+// Area light shape values
+const (
+	AreaRectangle AreaLightShape = iota
+	AreaSphere    AreaLightShape = iota
+)
+
+// Light is a Model with a light attribute. Like the FBX SDK's lights, directional and
+// spot lights shine down their local negative Y axis.
+// https://help.autodesk.com/view/FBX/2017/ENU/?guid=__cpp_ref_class_fbx_light_html
+type Light struct {
+	Object
+	LightType LightType
+	Color     floatgeom.Point3
+	// Intensity is a percentage, with 100 being full strength
+	Intensity  float64
+	DecayType  DecayType
+	DecayStart float64
+	// InnerAngle and OuterAngle are a spot light's cone angles in degrees. Its light is
+	// full strength inside the inner cone and fades out towards the outer one.
+	InnerAngle  float64
+	OuterAngle  float64
+	CastShadows bool
+	AreaShape   AreaLightShape
+}
+
+// NewLight creates a new Light with the FBX SDK's default properties
+func NewLight(scene *Scene, element *Element) *Light {
+	l := &Light{
+		Object:      *NewObject(scene, element),
+		Color:       floatgeom.Point3{1, 1, 1},
+		Intensity:   100,
+		OuterAngle:  45,
+		CastShadows: true,
+	}
+	l.Object.isNode = true
+	return l
+}
+
+// Type returns LIGHT
+func (l *Light) Type() Type {
+	return LIGHT
+}
+
+// postProcess reads the light's properties from its node attribute, which is
+// only connected once all objects are parsed
+func (l *Light) postProcess() bool {
+	attr := l.NodeAttribute()
+	if attr == nil {
+		return true
+	}
+	l.LightType = LightType(resolveEnumProperty(attr, "LightType", int(l.LightType)))
+	l.Color = resolveVec3Property(attr, "Color", l.Color)
+	l.Intensity = resolveNumberProperty(attr, "Intensity", l.Intensity)
+	l.DecayType = DecayType(resolveEnumProperty(attr, "DecayType", int(l.DecayType)))
+	l.DecayStart = resolveNumberProperty(attr, "DecayStart", l.DecayStart)
+	// Older files name the cone angles after the spot light's hotspot and cone
+	l.InnerAngle = resolveNumberProperty(attr, "InnerAngle", resolveNumberProperty(attr, "HotSpot", l.InnerAngle))
+	l.OuterAngle = resolveNumberProperty(attr, "OuterAngle", resolveNumberProperty(attr, "Cone angle", l.OuterAngle))
+	castShadows := 0
+	if l.CastShadows {
+		castShadows = 1
+	}
+	l.CastShadows = resolveEnumProperty(attr, "CastShadows", castShadows) != 0
+	l.AreaShape = AreaLightShape(resolveEnumProperty(attr, "AreaLightShape", int(l.AreaShape)))
+	return true
+}
+
+// ColorAt returns the light's color at time t of the given stack, or its static
+// color if stack is nil
+func (l *Light) ColorAt(stack *AnimationStack, t time.Duration) floatgeom.Point3 {
+	c := l.propertyAt(stack, "Color", []float64{l.Color.X(), l.Color.Y(), l.Color.Z()}, t)
+	return floatgeom.Point3{c[0], c[1], c[2]}
+}
+
+// IntensityAt returns the light's intensity at time t of the given stack, or its
+// static intensity if stack is nil
+func (l *Light) IntensityAt(stack *AnimationStack, t time.Duration) float64 {
+	return l.propertyAt(stack, "Intensity", []float64{l.Intensity}, t)[0]
+}
+
+// propertyAt returns the animated channels of one of the light's attribute properties
+func (l *Light) propertyAt(stack *AnimationStack, prop string, values []float64, t time.Duration) []float64 {
+	if stack == nil {
+		return values
+	}
+	if attr := l.NodeAttribute(); attr != nil {
+		values = stack.evaluateChannels(attr, prop, values, t)
+	}
+	return stack.evaluateChannels(l, prop, values, t)
+}
+
+func (l *Light) String() string {
+	return l.stringPrefix("")
+}
+
+func (l *Light) stringPrefix(prefix string) string {
+	s := prefix + "Light: " + l.Name() + "\n"
+	s += prefix + "\t" + fmt.Sprintf("type=%d color=%v intensity=%v decay=%d cone=%v,%v cast_shadows=%v", l.LightType, l.Color, l.Intensity, l.DecayType, l.InnerAngle, l.OuterAngle, l.CastShadows) + "\n"
+	s += l.Object.stringPrefix(prefix + "\t")
+	return s
+}
+
+// Lights returns the scene's lights, ordered by id
+func (s *Scene) Lights() []*Light {
+	out := make([]*Light, 0)
+	for _, o := range s.ObjectMap {
+		if l, ok := o.(*Light); ok {
+			out = append(out, l)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID() < out[j].ID() })
+	return out
+}
@@ -0,0 +1,42 @@
+package ofbx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLights(t *testing.T) {
+	scene := loadTestScene(t, "testdata/light.fbx")
+
+	lights := scene.Lights()
+	require.Len(t, lights, 2)
+	lamp, panel := lights[0], lights[1]
+	require.Equal(t, LIGHT, lamp.Type())
+	require.True(t, lamp.IsNode())
+	require.Equal(t, LightSpot, lamp.LightType)
+	require.Equal(t, floatgeom.Point3{1, 0.5, 0}, lamp.Color)
+	require.Equal(t, 150.0, lamp.Intensity)
+	require.Equal(t, DecayQuadratic, lamp.DecayType)
+	require.Equal(t, 20.0, lamp.InnerAngle)
+	require.Equal(t, 30.0, lamp.OuterAngle)
+	require.False(t, lamp.CastShadows)
+	require.Equal(t, floatgeom.Point3{0, 4, 0}, lamp.GlobalTransform().TransformPoint(floatgeom.Point3{}))
+
+	require.Equal(t, LightArea, panel.LightType)
+	require.Equal(t, AreaSphere, panel.AreaShape)
+	require.Equal(t, floatgeom.Point3{1, 1, 1}, panel.Color)
+	require.Equal(t, 100.0, panel.Intensity)
+	require.Equal(t, 45.0, panel.OuterAngle)
+	require.True(t, panel.CastShadows)
+
+	stack := scene.AnimationStacks[0]
+	require.Equal(t, lamp.Color, lamp.ColorAt(nil, 0))
+	// Only the blue channel is keyed
+	require.Equal(t, floatgeom.Point3{1, 0.5, 0.5}, lamp.ColorAt(stack, 500*time.Millisecond))
+	require.Equal(t, 150.0, lamp.IntensityAt(stack, 500*time.Millisecond))
+	require.Equal(t, 200.0, lamp.IntensityAt(stack, time.Second))
+	require.Equal(t, 100.0, panel.IntensityAt(stack, time.Second))
+}
@@ -1,6 +1,9 @@
 package ofbx
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // Material stores texture pointers and how to apply them
 type Material struct {
@@ -18,6 +21,9 @@ type Material struct {
 	ReflectionColor   Color
 	ReflectionFactor  float64
 	Textures          [TextureCOUNT]*Texture
+	// textures holds every connected texture by the property it is connected to,
+	// including properties without a TextureType
+	textures map[string]*Texture
 }
 
 // NewMaterial makes a stub Material
@@ -50,11 +56,38 @@ func (m *Material) stringPrefix(prefix string) string {
 	s += prefix + fmt.Sprintf("ShininessExponent: %f", m.ShininessExponent) + "\n"
 	s += prefix + "ReflectionColor" + m.ReflectionColor.String() + "\n"
 	s += prefix + fmt.Sprintf("ReflectionFactor: %f", m.ReflectionFactor) + "\n"
-	if m.Textures[DIFFUSE] != nil {
-		s += "Diffuse Texture: " + m.Textures[DIFFUSE].String() + "\n"
-	}
-	if m.Textures[NORMAL] != nil {
-		s += "Normal Texture: " + m.Textures[NORMAL].String() + "\n"
+	for _, slot := range m.TextureSlots() {
+		s += slot + " Texture: " + m.textures[slot].String() + "\n"
 	}
 	return s
 }
+
+// Texture returns the texture connected to the material property slot, like
+// "DiffuseColor" or "NormalMap", or nil if there is none
+func (m *Material) Texture(slot string) *Texture {
+	return m.textures[slot]
+}
+
+// TextureSlots returns the names of the properties the material has textures for, sorted
+func (m *Material) TextureSlots() []string {
+	slots := make([]string, 0, len(m.textures))
+	for slot := range m.textures {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+	return slots
+}
+
+// addTexture connects tex to the property slot. Only the first texture connected to a
+// slot is kept.
+func (m *Material) addTexture(slot string, tex *Texture) {
+	if m.textures == nil {
+		m.textures = make(map[string]*Texture)
+	}
+	if _, ok := m.textures[slot]; !ok {
+		m.textures[slot] = tex
+	}
+	if ttyp, ok := textureProperties[slot]; ok && m.Textures[ttyp] == nil {
+		m.Textures[ttyp] = tex
+	}
+}
@@ -0,0 +1,78 @@
+package ofbx
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const textureScene = `; FBX 7.4.0 project file
+Objects:  {
+	Geometry: 200, "Geometry::Quad", "Mesh" {
+		Vertices: *9 {
+			a: 0,0,0,1,0,0,1,1,0
+		} 
+		PolygonVertexIndex: *3 {
+			a: 0,1,-3
+		} 
+	}
+	Model: 100, "Model::Quad", "Mesh" {
+	}
+	Material: 600, "Material::Full", "" {
+		ShadingModel: "phong"
+	}
+	Texture: 700, "Texture::Specular", "" {
+		RelativeFilename: "spec.png"
+	}
+	Texture: 701, "Texture::Emissive", "" {
+		RelativeFilename: "glow.png"
+	}
+	Texture: 702, "Texture::Bump", "" {
+		RelativeFilename: "bump.png"
+	}
+	Texture: 703, "Texture::Roughness", "" {
+		RelativeFilename: "rough.png"
+	}
+	Texture: 704, "Texture::Other", "" {
+		RelativeFilename: "other.png"
+	}
+}
+Connections:  {
+	C: "OO",100,0
+	C: "OO",200,100
+	C: "OO",600,100
+	C: "OP",700,600, "SpecularColor"
+	C: "OP",701,600, "EmissiveColor"
+	C: "OP",702,600, "Bump"
+	C: "OP",703,600, "Maya|roughness"
+	C: "OP",704,600, "SpecularColor"
+}
+`
+
+func TestMaterialTextures(t *testing.T) {
+	scene, err := Load(strings.NewReader(textureScene))
+	require.Nil(t, err)
+	mat := scene.Meshes[0].Materials[0]
+	require.Nil(t, mat.Textures[DIFFUSE])
+	require.Equal(t, "spec.png", mat.Textures[SPECULAR].RelativeFilename)
+	require.Equal(t, "glow.png", mat.Textures[EMISSIVE].RelativeFilename)
+	require.Equal(t, "bump.png", mat.Textures[BUMP].RelativeFilename)
+	require.Equal(t, []string{"Bump", "EmissiveColor", "Maya|roughness", "SpecularColor"}, mat.TextureSlots())
+	require.Equal(t, "rough.png", mat.Texture("Maya|roughness").RelativeFilename)
+	// The first texture connected to a slot is kept
+	require.Equal(t, mat.Textures[SPECULAR], mat.Texture("SpecularColor"))
+	require.Nil(t, mat.Texture("DiffuseColor"))
+	require.Equal(t, "vector displacement", VECTOR_DISPLACEMENT.String())
+
+	cube := loadTestScene(t, "testdata/cube.fbx").Meshes[0].Materials[0]
+	require.Equal(t, cube.Textures[DIFFUSE], cube.Texture("DiffuseColor"))
+
+	mtl := &bytes.Buffer{}
+	mw := &textWriter{Writer: bufio.NewWriter(mtl)}
+	writeMTLMaterial(mw, mat, "Full")
+	require.Nil(t, mw.Flush())
+	require.Contains(t, mtl.String(), "map_Ks spec.png\nmap_Ke glow.png\nmap_Bump bump.png\n")
+}
@@ -35,11 +35,37 @@ const (
 	CoordSystemLeft  CoordSystem = iota
 )
 
-// Matrix is a 16 sized slice that we operate on as if it was actually a matrix
+// Matrix is a 4x4 transformation matrix stored in column major order,
+// with the translation in elements 12, 13 and 14
 type Matrix struct {
 	m [16]float64 // last 4 are translation
 }
 
+// NewMatrix creates a Matrix from column major elements
+func NewMatrix(elements [16]float64) Matrix {
+	return Matrix{elements}
+}
+
+// IdentityMatrix returns a Matrix which transforms nothing
+func IdentityMatrix() Matrix {
+	return Matrix{[16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}}
+}
+
+// Elements returns the matrix's values in column major order
+func (m1 Matrix) Elements() [16]float64 {
+	return m1.m
+}
+
+// At returns the element at the given row and column
+func (m1 Matrix) At(row, col int) float64 {
+	return m1.m[col*4+row]
+}
+
+// Set sets the element at the given row and column
+func (m1 *Matrix) Set(row, col int, v float64) {
+	m1.m[col*4+row] = v
+}
+
 func matrixFromSlice(fs []float64) (Matrix, error) {
 	if len(fs) != 16 {
 		return Matrix{}, fmt.Errorf("Expected 16 values, got %d", len(fs))
@@ -49,11 +75,6 @@ func matrixFromSlice(fs []float64) (Matrix, error) {
 	return Matrix{a}, nil
 }
 
-// Quat probably can bve removed
-type Quat struct {
-	X, Y, Z, w float64
-}
-
 // Mul multiplies the values of two matricies together and returns the output
 func (m1 Matrix) Mul(m2 Matrix) Matrix {
 	res := [16]float64{}
@@ -69,18 +90,130 @@ func (m1 Matrix) Mul(m2 Matrix) Matrix {
 	return Matrix{res}
 }
 
+// Transpose returns the matrix with its rows and columns swapped
+func (m1 Matrix) Transpose() Matrix {
+	var res Matrix
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			res.m[row*4+col] = m1.m[col*4+row]
+		}
+	}
+	return res
+}
+
+// Determinant returns the determinant of the matrix
+func (m1 Matrix) Determinant() float64 {
+	m := m1.m
+	return m[0]*m1.cofactor(0) + m[1]*m1.cofactor(4) + m[2]*m1.cofactor(8) + m[3]*m1.cofactor(12)
+}
+
+// Inverse returns the inverse of the matrix. ok is false, and the
+// identity is returned, if the matrix is singular.
+func (m1 Matrix) Inverse() (inv Matrix, ok bool) {
+	for i := range inv.m {
+		inv.m[i] = m1.cofactor(i)
+	}
+	m := m1.m
+	det := m[0]*inv.m[0] + m[1]*inv.m[4] + m[2]*inv.m[8] + m[3]*inv.m[12]
+	if det == 0 {
+		return IdentityMatrix(), false
+	}
+	for i := range inv.m {
+		inv.m[i] /= det
+	}
+	return inv, true
+}
+
+// cofactor returns element i of the matrix's adjugate
+func (m1 Matrix) cofactor(i int) float64 {
+	m := m1.m
+	switch i {
+	case 0:
+		return m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	case 1:
+		return -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	case 2:
+		return m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	case 3:
+		return -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	case 4:
+		return -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	case 5:
+		return m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	case 6:
+		return -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	case 7:
+		return m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	case 8:
+		return m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	case 9:
+		return -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	case 10:
+		return m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	case 11:
+		return -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	case 12:
+		return -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	case 13:
+		return m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	case 14:
+		return -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	}
+	return m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+}
+
+// TransformPoint applies the full transform to a point
+func (m1 Matrix) TransformPoint(p floatgeom.Point3) floatgeom.Point3 {
+	return floatgeom.Point3{
+		m1.m[0]*p[0] + m1.m[4]*p[1] + m1.m[8]*p[2] + m1.m[12],
+		m1.m[1]*p[0] + m1.m[5]*p[1] + m1.m[9]*p[2] + m1.m[13],
+		m1.m[2]*p[0] + m1.m[6]*p[1] + m1.m[10]*p[2] + m1.m[14],
+	}
+}
+
+// TransformDirection applies the transform without its translation to a direction.
+// The result is not normalized.
+func (m1 Matrix) TransformDirection(d floatgeom.Point3) floatgeom.Point3 {
+	return floatgeom.Point3{
+		m1.m[0]*d[0] + m1.m[4]*d[1] + m1.m[8]*d[2],
+		m1.m[1]*d[0] + m1.m[5]*d[1] + m1.m[9]*d[2],
+		m1.m[2]*d[0] + m1.m[6]*d[1] + m1.m[10]*d[2],
+	}
+}
+
+// Decompose splits an affine matrix into its translation, rotation and scale, such that
+// the matrix is translation * rotation * scale. Shear and projection are lost.
+func (m1 Matrix) Decompose() (translation floatgeom.Point3, rotation Quat, scale floatgeom.Point3) {
+	m := m1.m
+	translation = floatgeom.Point3{m[12], m[13], m[14]}
+	for i := 0; i < 3; i++ {
+		scale[i] = math.Sqrt(m[i*4]*m[i*4] + m[i*4+1]*m[i*4+1] + m[i*4+2]*m[i*4+2])
+	}
+	// A mirroring transform is represented as a negative x scale
+	det := m[0]*(m[5]*m[10]-m[9]*m[6]) - m[4]*(m[1]*m[10]-m[9]*m[2]) + m[8]*(m[1]*m[6]-m[5]*m[2])
+	if det < 0 {
+		scale[0] = -scale[0]
+	}
+
+	var rot [9]float64
+	for c := 0; c < 3; c++ {
+		for row := 0; row < 3; row++ {
+			if scale[c] != 0 {
+				rot[c*3+row] = m[c*4+row] / scale[c]
+			}
+		}
+	}
+	return translation, quatFromRotation(rot), scale
+}
+
 func setTranslation(v floatgeom.Point3, m *Matrix) {
 	m.m[12] = v.X()
 	m.m[13] = v.Y()
 	m.m[14] = v.Z()
 }
 
-func makeIdentity() Matrix {
-	return Matrix{[16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}}
-}
-
 func rotationX(angle float64) Matrix {
-	m2 := makeIdentity()
+	m2 := IdentityMatrix()
 	//radian
 	c := math.Cos(angle)
 	s := math.Sin(angle)
@@ -92,7 +225,7 @@ func rotationX(angle float64) Matrix {
 }
 
 func rotationY(angle float64) Matrix {
-	m2 := makeIdentity()
+	m2 := IdentityMatrix()
 	//radian
 	c := math.Cos(angle)
 	s := math.Sin(angle)
@@ -104,7 +237,7 @@ func rotationY(angle float64) Matrix {
 }
 
 func rotationZ(angle float64) Matrix {
-	m2 := makeIdentity()
+	m2 := IdentityMatrix()
 	//radian
 	c := math.Cos(angle)
 	s := math.Sin(angle)
@@ -114,11 +247,3 @@ func rotationZ(angle float64) Matrix {
 	m2.m[1] = s
 	return m2
 }
-
-func getTriCountFromPoly(indices []int, idx int) (int, int) {
-	count := 1
-	for indices[idx+1+count] >= 0 {
-		count++
-	}
-	return count, idx
-}
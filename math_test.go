@@ -0,0 +1,84 @@
+package ofbx
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func trsMatrix(t, r, s floatgeom.Point3) Matrix {
+	tm := IdentityMatrix()
+	setTranslation(t, &tm)
+	sm := IdentityMatrix()
+	sm.Set(0, 0, s.X())
+	sm.Set(1, 1, s.Y())
+	sm.Set(2, 2, s.Z())
+	return tm.Mul(EulerXYZ.rotationMatrix(r)).Mul(sm)
+}
+
+func requireMatrixInDelta(t *testing.T, expected, actual Matrix) {
+	t.Helper()
+	for i := range expected.m {
+		require.InDelta(t, expected.m[i], actual.m[i], 1e-9, "element %d", i)
+	}
+}
+
+func TestMatrixInverse(t *testing.T) {
+	m := trsMatrix(floatgeom.Point3{1, -2, 3}, floatgeom.Point3{30, 45, -60}, floatgeom.Point3{2, 0.5, 3})
+	inv, ok := m.Inverse()
+	require.True(t, ok)
+	requireMatrixInDelta(t, IdentityMatrix(), m.Mul(inv))
+	requireMatrixInDelta(t, IdentityMatrix(), inv.Mul(m))
+	require.InDelta(t, 3.0, m.Determinant(), 1e-9)
+
+	_, ok = NewMatrix([16]float64{}).Inverse()
+	require.False(t, ok)
+}
+
+func TestMatrixAccess(t *testing.T) {
+	m := IdentityMatrix()
+	m.Set(1, 3, 5)
+	require.Equal(t, 5.0, m.At(1, 3))
+	require.Equal(t, 5.0, m.Elements()[13])
+	require.Equal(t, 5.0, m.Transpose().At(3, 1))
+	require.Equal(t, m, m.Transpose().Transpose())
+}
+
+func TestMatrixTransform(t *testing.T) {
+	m := trsMatrix(floatgeom.Point3{1, 2, 3}, floatgeom.Point3{0, 0, 90}, floatgeom.Point3{2, 2, 2})
+	p := m.TransformPoint(floatgeom.Point3{1, 0, 0})
+	require.InDelta(t, 1.0, p.X(), 1e-9)
+	require.InDelta(t, 4.0, p.Y(), 1e-9)
+	require.InDelta(t, 3.0, p.Z(), 1e-9)
+	d := m.TransformDirection(floatgeom.Point3{1, 0, 0})
+	require.InDelta(t, 0.0, d.X(), 1e-9)
+	require.InDelta(t, 2.0, d.Y(), 1e-9)
+}
+
+func TestMatrixDecompose(t *testing.T) {
+	type testCase struct {
+		t, r, s floatgeom.Point3
+	}
+	testCases := []testCase{
+		{floatgeom.Point3{}, floatgeom.Point3{}, floatgeom.Point3{1, 1, 1}},
+		{floatgeom.Point3{1, -2, 3}, floatgeom.Point3{30, 45, -60}, floatgeom.Point3{2, 0.5, 3}},
+		{floatgeom.Point3{0, 0, 5}, floatgeom.Point3{180, 0, 0}, floatgeom.Point3{1, 1, 1}},
+		{floatgeom.Point3{}, floatgeom.Point3{10, 20, 30}, floatgeom.Point3{-1, 2, 2}},
+	}
+	for _, tc := range testCases {
+		m := trsMatrix(tc.t, tc.r, tc.s)
+		translation, rotation, scale := m.Decompose()
+		require.Equal(t, tc.t, translation)
+		require.InDelta(t, 1.0, rotation.Length(), 1e-9)
+		// Rebuilding from the decomposition gives back the matrix
+		r := rotation.Matrix()
+		sm := IdentityMatrix()
+		sm.Set(0, 0, scale.X())
+		sm.Set(1, 1, scale.Y())
+		sm.Set(2, 2, scale.Z())
+		tm := IdentityMatrix()
+		setTranslation(translation, &tm)
+		requireMatrixInDelta(t, m, tm.Mul(r).Mul(sm))
+	}
+}
@@ -56,19 +56,22 @@ func (m *Mesh) Type() Type {
 	return MESH
 }
 
-func (m *Mesh) getGeometricMatrix() Matrix {
+// GeometricTransform returns the transform applied to the Mesh's Geometry, on top of
+// the Mesh's own transform. Unlike the Mesh's transform it is not inherited by children.
+// The Geometry is scaled, then rotated, then translated.
+func (m *Mesh) GeometricTransform() Matrix {
 	translation := resolveVec3Property(m, "GeometricTranslation", floatgeom.Point3{0, 0, 0})
 	rotation := resolveVec3Property(m, "GeometricRotation", floatgeom.Point3{0, 0, 0})
 	scale := resolveVec3Property(m, "GeometricScaling", floatgeom.Point3{1, 1, 1})
 
-	scaleMtx := makeIdentity()
+	scaleMtx := IdentityMatrix()
 	scaleMtx.m[0] = scale.X()
 	scaleMtx.m[5] = scale.Y()
 	scaleMtx.m[10] = scale.Z()
 	mtx := EulerXYZ.rotationMatrix(rotation)
 	setTranslation(translation, &mtx)
 
-	return scaleMtx.Mul(mtx)
+	return mtx.Mul(scaleMtx)
 }
 
 func (m *Mesh) String() string {
@@ -12,7 +12,20 @@ func resolveEnumProperty(object Obj, name string, defaultVal int) int {
 		return defaultVal
 	}
 
-	return int(x.value.toInt32())
+	return int(x.integer())
+}
+
+func resolveNumberProperty(object Obj, name string, defaultVal float64) float64 {
+	element := resolveProperty(object, name)
+	if element == nil {
+		return defaultVal
+	}
+	x := element.getProperty(4)
+	if x == nil {
+		return defaultVal
+	}
+
+	return x.number()
 }
 
 func resolveVec3Property(object Obj, name string, defaultVal floatgeom.Point3) floatgeom.Point3 {
@@ -25,9 +38,9 @@ func resolveVec3Property(object Obj, name string, defaultVal floatgeom.Point3) f
 	}
 
 	return floatgeom.Point3{
-		element.getProperty(4).value.toDouble(),
-		element.getProperty(5).value.toDouble(),
-		element.getProperty(6).value.toDouble(),
+		element.getProperty(4).number(),
+		element.getProperty(5).number(),
+		element.getProperty(6).number(),
 	}
 }
 
@@ -50,7 +63,7 @@ func splatVec2(mapping VertexDataMapping, data []floatgeom.Point2, indices []int
 		//  v0  v1 ...
 		// uv0 uv1 ...
 
-		out := make([]floatgeom.Point2, len(origIndices))
+		out = make([]floatgeom.Point2, len(origIndices))
 
 		for i := 0; i < len(origIndices); i++ {
 			idx := origIndices[i]
@@ -88,7 +101,7 @@ func splatVec3(mapping VertexDataMapping, data []floatgeom.Point3, indices []int
 		//  v0  v1 ...
 		// uv0 uv1 ...
 
-		out := make([]floatgeom.Point3, len(origIndices))
+		out = make([]floatgeom.Point3, len(origIndices))
 
 		for i := 0; i < len(origIndices); i++ {
 			idx := origIndices[i]
@@ -126,7 +139,7 @@ func splatVec4(mapping VertexDataMapping, data []floatgeom.Point4, indices []int
 		//  v0  v1 ...
 		// uv0 uv1 ...
 
-		out := make([]floatgeom.Point4, len(origIndices))
+		out = make([]floatgeom.Point4, len(origIndices))
 
 		for i := 0; i < len(origIndices); i++ {
 			idx := origIndices[i]
@@ -144,51 +157,3 @@ func splatVec4(mapping VertexDataMapping, data []floatgeom.Point4, indices []int
 	}
 	return out
 }
-
-func remapVec2(out *[]floatgeom.Point2, m []int) {
-	if len(*out) == 0 {
-		return
-	}
-
-	old := make([]floatgeom.Point2, len(*out))
-	copy(old, *out)
-	for i := 0; i < len(m); i++ {
-		if m[i] < len(old) {
-			*out = append(*out, old[m[i]])
-		} else {
-			*out = append(*out, floatgeom.Point2{})
-		}
-	}
-}
-
-func remapVec3(out *[]floatgeom.Point3, m []int) {
-	if len(*out) == 0 {
-		return
-	}
-
-	old := make([]floatgeom.Point3, len(*out))
-	copy(old, *out)
-	for i := 0; i < len(m); i++ {
-		if m[i] < len(old) {
-			*out = append(*out, old[m[i]])
-		} else {
-			*out = append(*out, floatgeom.Point3{})
-		}
-	}
-}
-
-func remapVec4(out *[]floatgeom.Point4, m []int) {
-	if len(*out) == 0 {
-		return
-	}
-
-	old := make([]floatgeom.Point4, len(*out))
-	copy(old, *out)
-	for i := 0; i < len(m); i++ {
-		if m[i] < len(old) {
-			*out = append(*out, old[m[i]])
-		} else {
-			*out = append(*out, floatgeom.Point4{})
-		}
-	}
-}
@@ -2,6 +2,8 @@ package ofbx
 
 import (
 	"io"
+	"strings"
+	"time"
 
 	"github.com/oakmound/oak/v2/alg/floatgeom"
 )
@@ -15,6 +17,10 @@ type Object struct {
 
 	isNode bool
 	scene  *Scene
+
+	// parent and children link nodes into the scene graph
+	parent   Obj
+	children []Obj
 }
 
 // Obj interface version of Object
@@ -27,9 +33,22 @@ type Obj interface {
 	SetNodeAttribute(na Obj)
 	IsNode() bool
 	Scene() *Scene
+	Properties() []ObjectProperty
+	Property(name string) (ObjectProperty, bool)
+	UserProperties() map[string]PropertyValue
 	Type() Type
+	LocalTransform() Matrix
+	LocalTransformAt(stack *AnimationStack, t time.Duration) Matrix
+	GlobalTransform() Matrix
+	GlobalTransformAt(stack *AnimationStack, t time.Duration) Matrix
+	Parent() Obj
+	Children() []Obj
+	Walk(fn func(Obj) bool)
+	FindByName(name string) Obj
 	String() string
 	stringPrefix(string) string
+	setParent(Obj)
+	addChild(Obj)
 }
 
 // ID returns the Object's integer id value
@@ -72,6 +91,83 @@ func (o *Object) Scene() *Scene {
 	return o.scene
 }
 
+// GlobalTransform returns the Object's transform in world space
+func (o *Object) GlobalTransform() Matrix {
+	return getGlobalTransform(o)
+}
+
+// GlobalTransformAt returns the Object's transform in world space at time t of the
+// given stack, with the Object and its ancestors animated
+func (o *Object) GlobalTransformAt(stack *AnimationStack, t time.Duration) Matrix {
+	local := o.LocalTransformAt(stack, t)
+	if o.parent == nil {
+		return local
+	}
+	return o.parent.GlobalTransformAt(stack, t).Mul(local)
+}
+
+// Parent returns the node this node is attached to, which is the Scene's
+// RootNode for top level nodes, or nil if this is not a node
+func (o *Object) Parent() Obj {
+	return o.parent
+}
+
+// Children returns the nodes attached to this node
+func (o *Object) Children() []Obj {
+	return o.children
+}
+
+// Walk calls fn on each of this node's descendants, depth first with parents before their
+// children. If fn returns false the children of the node it was called on are skipped.
+func (o *Object) Walk(fn func(Obj) bool) {
+	for _, c := range o.children {
+		if fn(c) {
+			c.Walk(fn)
+		}
+	}
+}
+
+// FindByName returns the first descendant, in Walk order, named name. Names can
+// include their class, as in Model::Cube, or not, as in Cube.
+func (o *Object) FindByName(name string) Obj {
+	var found Obj
+	o.Walk(func(c Obj) bool {
+		if found == nil && (c.Name() == name || BaseName(c.Name()) == name) {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+func (o *Object) setParent(parent Obj) {
+	o.parent = parent
+}
+
+func (o *Object) addChild(child Obj) {
+	o.children = append(o.children, child)
+}
+
+// Type returns NOTYPE, types embedding Object return their own Type
+func (o *Object) Type() Type {
+	return NOTYPE
+}
+
+// LocalTransform returns the Object's transform relative to its parent
+func (o *Object) LocalTransform() Matrix {
+	return getLocalTransform(o)
+}
+
+// LocalTransformAt returns the Object's transform relative to its parent at time t
+// of the given stack, with the stack's layers blended together. Properties no layer
+// animates keep their static values.
+func (o *Object) LocalTransformAt(stack *AnimationStack, t time.Duration) Matrix {
+	if stack == nil {
+		return getLocalTransform(o)
+	}
+	return stack.evaluateNode(o, t)
+}
+
 func (o *Object) String() string {
 	return o.stringPrefix("")
 }
@@ -103,6 +199,17 @@ func NewObject(scene *Scene, e *Element) *Object {
 	return o
 }
 
+// BaseName strips the class from binary, Cube\x00\x01Model, and ASCII, Model::Cube, object names
+func BaseName(s string) string {
+	if idx := strings.Index(s, "\x00\x01"); idx != -1 {
+		return s[:idx]
+	}
+	if idx := strings.Index(s, "::"); idx != -1 {
+		return s[idx+2:]
+	}
+	return s
+}
+
 func resolveAllObjectLinks(o Obj) []Obj {
 	return resolveObjectLinks(o, NOTYPE, []string{""})
 }
@@ -167,18 +274,6 @@ func resolveObjectLinkReverse(o Obj, typ Type) Obj {
 	return nil
 }
 
-func getParent(o Obj) Obj {
-	for _, con := range o.Scene().Connections {
-		if con.from == o.ID() {
-			obj := o.Scene().ObjectMap[con.to]
-			if obj != nil && obj.IsNode() {
-				return obj
-			}
-		}
-	}
-	return nil
-}
-
 func getRotationOrder(o Obj) RotationOrder {
 	return RotationOrder(resolveEnumProperty(o, "RotationOrder", int(EulerXYZ)))
 }
@@ -220,57 +315,55 @@ func getLocalScaling(o Obj) floatgeom.Point3 {
 }
 
 func getGlobalTransform(o Obj) Matrix {
-	parent := getParent(o)
+	parent := o.Parent()
 	if parent == nil {
-		return evalLocal(o, getLocalTranslation(o), getLocalRotation(o))
+		return getLocalTransform(o)
 	}
-
-	return getGlobalTransform(parent).Mul(evalLocal(o, getLocalTranslation(o), getLocalRotation(o)))
+	return getGlobalTransform(parent).Mul(getLocalTransform(o))
 }
 
 func getLocalTransform(o Obj) Matrix {
 	return evalLocalScaling(o, getLocalTranslation(o), getLocalRotation(o), getLocalScaling(o))
 }
 
-func evalLocal(o Obj, translation, rotation floatgeom.Point3) Matrix {
-	return evalLocalScaling(o, translation, rotation, getLocalScaling(o))
+func evalLocalScaling(o Obj, translation, rotation, scaling floatgeom.Point3) Matrix {
+	return evalLocalTransform(o, translation, getRotationOrder(o).rotationMatrix(rotation), scaling)
 }
 
-func evalLocalScaling(o Obj, translation, rotation, scaling floatgeom.Point3) Matrix {
+// evalLocalTransform builds a local transform from its parts, with r the rotation matrix of Lcl Rotation
+func evalLocalTransform(o Obj, translation floatgeom.Point3, r Matrix, scaling floatgeom.Point3) Matrix {
 	rotationPivot := getRotationPivot(o)
 	scalingPivot := getScalingPivot(o)
-	rotationOrder := getRotationOrder(o)
 
-	s := makeIdentity()
+	s := IdentityMatrix()
 	s.m[0] = scaling.X()
 	s.m[5] = scaling.Y()
 	s.m[10] = scaling.Z()
 
-	t := makeIdentity()
+	t := IdentityMatrix()
 	setTranslation(translation, &t)
 
-	r := rotationOrder.rotationMatrix(rotation)
 	pr := getPreRotation(o)
 	rPre := EulerXYZ.rotationMatrix(pr)
 	psr := getPostRotation(o)
 	rPostInv := EulerZYX.rotationMatrix(psr.MulConst(-1))
 
-	rOff := makeIdentity()
+	rOff := IdentityMatrix()
 	setTranslation(getRotationOffset(o), &rOff)
 
-	rP := makeIdentity()
+	rP := IdentityMatrix()
 	setTranslation(rotationPivot, &rP)
 
-	rPInv := makeIdentity()
+	rPInv := IdentityMatrix()
 	setTranslation(rotationPivot.MulConst(-1), &rPInv)
 
-	sOff := makeIdentity()
+	sOff := IdentityMatrix()
 	setTranslation(getScalingOffset(o), &sOff)
 
-	sP := makeIdentity()
+	sP := IdentityMatrix()
 	setTranslation(scalingPivot, &sP)
 
-	sPInv := makeIdentity()
+	sPInv := IdentityMatrix()
 	setTranslation(scalingPivot.MulConst(-1), &sPInv)
 
 	// http://help.autodesk.com/view/FBX/2017/ENU/?guid=__files_GUID_10CDD63C_79C1_4F2D_BB28_AD2BE65A02ED_htm
@@ -0,0 +1,312 @@
+package ofbx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// PropertyKind is the Go type an ObjectProperty's Value holds
+type PropertyKind int
+
+// Property kinds
+const (
+	// PropertyUnknown values are nil, e.g. for object references
+	PropertyUnknown PropertyKind = iota
+	// PropertyBool values are bools
+	PropertyBool PropertyKind = iota
+	// PropertyInt values are int64s, including enums
+	PropertyInt PropertyKind = iota
+	// PropertyDouble values are float64s
+	PropertyDouble PropertyKind = iota
+	// PropertyVec3 values are floatgeom.Point3s
+	PropertyVec3 PropertyKind = iota
+	// PropertyColor values are floatgeom.Point3s of red, green and blue
+	PropertyColor PropertyKind = iota
+	// PropertyString values are strings
+	PropertyString PropertyKind = iota
+	// PropertyTime values are time.Durations
+	PropertyTime PropertyKind = iota
+)
+
+var propertyKinds = map[string]PropertyKind{
+	"bool":            PropertyBool,
+	"Bool":            PropertyBool,
+	"int":             PropertyInt,
+	"Integer":         PropertyInt,
+	"enum":            PropertyInt,
+	"Enum":            PropertyInt,
+	"ULongLong":       PropertyInt,
+	"LongLong":        PropertyInt,
+	"double":          PropertyDouble,
+	"Number":          PropertyDouble,
+	"float":           PropertyDouble,
+	"Float":           PropertyDouble,
+	"Vector":          PropertyVec3,
+	"Vector3D":        PropertyVec3,
+	"Lcl Translation": PropertyVec3,
+	"Lcl Rotation":    PropertyVec3,
+	"Lcl Scaling":     PropertyVec3,
+	"Color":           PropertyColor,
+	"ColorRGB":        PropertyColor,
+	"KString":         PropertyString,
+	"DateTime":        PropertyString,
+	"Url":             PropertyString,
+	"XRefUrl":         PropertyString,
+	"KTime":           PropertyTime,
+}
+
+// ObjectProperty is one of an Object's Properties70 entries
+type ObjectProperty struct {
+	Name string
+	// Type and Label are the FBX type names of the property, e.g. "Lcl Translation" or
+	// "double" and "Number"
+	Type  string
+	Label string
+	// Flags are the property's FBX flags, e.g. "A" for animatable or "U" for user defined
+	Flags string
+	Kind  PropertyKind
+	Value interface{}
+	// FromTemplate is set for properties the object doesn't have, taken from the
+	// Definitions template for its class
+	FromTemplate bool
+}
+
+func newObjectProperty(elem *Element, fromTemplate bool) (ObjectProperty, bool) {
+	if !isString(elem.getProperty(0)) {
+		return ObjectProperty{}, false
+	}
+	p := ObjectProperty{Name: elem.getProperty(0).value.String(), FromTemplate: fromTemplate}
+	for i, s := range []*string{&p.Type, &p.Label, &p.Flags} {
+		if prop := elem.getProperty(i + 1); isString(prop) {
+			*s = prop.value.String()
+		}
+	}
+	var values []*Property
+	if len(elem.Properties) > 4 {
+		values = elem.Properties[4:]
+	}
+	p.Kind = propertyKind(p.Type, values)
+	switch p.Kind {
+	case PropertyBool:
+		p.Value = values[0].number() != 0
+	case PropertyInt:
+		p.Value = values[0].integer()
+	case PropertyDouble:
+		p.Value = values[0].number()
+	case PropertyVec3, PropertyColor:
+		p.Value = floatgeom.Point3{values[0].number(), values[1].number(), values[2].number()}
+	case PropertyString:
+		p.Value = values[0].value.String()
+	case PropertyTime:
+		p.Value = fbxTimetoStdTime(values[0].integer())
+	}
+	return p, true
+}
+
+// propertyKind returns the kind of a property with the given type name, inferring it from
+// its values for type names it doesn't know
+func propertyKind(typ string, values []*Property) PropertyKind {
+	kind, ok := propertyKinds[typ]
+	switch {
+	case ok && (kind == PropertyVec3 || kind == PropertyColor):
+		if len(values) >= 3 {
+			return kind
+		}
+	case ok && len(values) != 0:
+		if kind != PropertyString || isString(values[0]) {
+			return kind
+		}
+	case len(values) == 3:
+		return PropertyVec3
+	case len(values) == 1:
+		switch values[0].Type {
+		case STRING, RAWSTRING:
+			return PropertyString
+		case BOOL:
+			return PropertyBool
+		case INT16, INTEGER, LONG:
+			return PropertyInt
+		case FLOAT, DOUBLE:
+			return PropertyDouble
+		}
+	}
+	return PropertyUnknown
+}
+
+// Bool returns the property's value as a bool, and whether it is one
+func (p ObjectProperty) Bool() (bool, bool) {
+	if p.Kind == PropertyInt {
+		return p.Value.(int64) != 0, true
+	}
+	b, ok := p.Value.(bool)
+	return b, ok
+}
+
+// Float returns a numeric property's value as a float64, and whether it is numeric
+func (p ObjectProperty) Float() (float64, bool) {
+	switch v := p.Value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// Vec3 returns a vector or color property's value, and whether it is one
+func (p ObjectProperty) Vec3() (floatgeom.Point3, bool) {
+	v, ok := p.Value.(floatgeom.Point3)
+	return v, ok
+}
+
+func (p ObjectProperty) String() string {
+	return fmt.Sprintf("%s (%s) = %v", p.Name, p.Type, p.Value)
+}
+
+// Properties returns the object's Properties70 entries in the order they were written,
+// followed by any its Definitions template has which it doesn't
+func (o *Object) Properties() []ObjectProperty {
+	var out []ObjectProperty
+	seen := map[string]bool{}
+	for _, elem := range properties70(o.element) {
+		if p, ok := newObjectProperty(elem, false); ok {
+			out = append(out, p)
+			seen[p.Name] = true
+		}
+	}
+	for _, elem := range properties70(o.scene.template(o.element)) {
+		if p, ok := newObjectProperty(elem, true); ok && !seen[p.Name] {
+			out = append(out, p)
+			seen[p.Name] = true
+		}
+	}
+	return out
+}
+
+// Property returns the object's property with the given name, falling back to its
+// Definitions template
+func (o *Object) Property(name string) (ObjectProperty, bool) {
+	if elem := resolveProperty(o, name); elem != nil {
+		return newObjectProperty(elem, findProperty70(o.element, name) == nil)
+	}
+	return ObjectProperty{}, false
+}
+
+// properties70 returns the entries of element's Properties70 child
+func properties70(element *Element) []*Element {
+	if element == nil {
+		return nil
+	}
+	elems := findChildren(element, "Properties70")
+	if len(elems) == 0 {
+		return nil
+	}
+	return elems[0].Children
+}
+
+// findProperty70 returns the Properties70 entry of element with the given name
+func findProperty70(element *Element, name string) *Element {
+	for _, elem := range properties70(element) {
+		if prop := elem.getProperty(0); prop != nil && prop.value.String() == name {
+			return elem
+		}
+	}
+	return nil
+}
+
+// template returns the PropertyTemplate for objects written as element. Object types with
+// several templates, like NodeAttribute, are matched by class or shading model.
+func (s *Scene) template(element *Element) *Element {
+	if s == nil || element == nil || element.ID == nil {
+		return nil
+	}
+	templates := s.templates[element.ID.String()]
+	if len(templates) == 1 {
+		return templates[0]
+	}
+	var hints []string
+	if class := element.getProperty(2); isString(class) && class.value.String() != "" {
+		hints = append(hints, "Fbx"+class.value.String())
+		if class.value.String() == "LimbNode" {
+			hints = append(hints, "FbxSkeleton")
+		}
+	}
+	if shading := findSingleChildProperty(element, "ShadingModel"); isString(shading) {
+		hints = append(hints, "FbxSurface"+shading.value.String())
+	}
+	for _, t := range templates {
+		name := t.getProperty(0).value.String()
+		for _, hint := range hints {
+			if strings.EqualFold(name, hint) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// number returns a scalar property's value as a float64, whatever its type
+func (p *Property) number() float64 {
+	p.value.Seek(0, io.SeekStart)
+	switch p.Type {
+	case DOUBLE:
+		return p.value.toDouble()
+	case FLOAT:
+		return float64(p.value.toFloat())
+	case BOOL:
+		return float64(p.boolByte())
+	}
+	return float64(p.integer())
+}
+
+// integer returns a scalar property's value as an int64, whatever its type
+func (p *Property) integer() int64 {
+	p.value.Seek(0, io.SeekStart)
+	if p.value.isText {
+		if p.Type == BOOL {
+			return int64(p.boolByte())
+		}
+		return p.value.textInt64()
+	}
+	switch p.Type {
+	case LONG:
+		return p.value.toint64()
+	case INTEGER:
+		return int64(p.value.toInt32())
+	case INT16:
+		b := p.value.bytes()
+		if len(b) < 2 {
+			return 0
+		}
+		return int64(int16(uint16(b[0]) | uint16(b[1])<<8))
+	case BOOL:
+		return int64(p.boolByte())
+	case DOUBLE:
+		return int64(p.value.toDouble())
+	case FLOAT:
+		return int64(p.value.toFloat())
+	}
+	return 0
+}
+
+// boolByte returns 1 for a true BOOL property and 0 otherwise. ASCII files write
+// bools as letters, like Y or T.
+func (p *Property) boolByte() int {
+	b := p.value.bytes()
+	if len(b) == 0 {
+		return 0
+	}
+	switch b[0] {
+	case 0, 'F', 'N', '0':
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,114 @@
+package ofbx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectProperties(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+
+	cube := scene.Meshes[0]
+	props := cube.Properties()
+	require.Equal(t, "Lcl Translation", props[0].Name)
+	require.Equal(t, PropertyVec3, props[0].Kind)
+	require.Equal(t, floatgeom.Point3{1.5, -2, 0.3}, props[0].Value)
+	require.Equal(t, "A", props[0].Flags)
+	require.False(t, props[0].FromTemplate)
+
+	scaling, ok := cube.Property("Lcl Scaling")
+	require.True(t, ok)
+	require.True(t, scaling.FromTemplate)
+	require.Equal(t, floatgeom.Point3{1, 1, 1}, scaling.Value)
+	order, ok := cube.Property("RotationOrder")
+	require.True(t, ok)
+	require.Equal(t, PropertyInt, order.Kind)
+	require.Equal(t, int64(0), order.Value)
+	_, ok = cube.Property("Missing")
+	require.False(t, ok)
+
+	red := scene.Meshes[0].Materials[0]
+	shading, ok := red.Property("ShadingModel")
+	require.True(t, ok)
+	require.Equal(t, PropertyString, shading.Kind)
+	require.Equal(t, "Phong", shading.Value)
+	// Unset material values come from the template
+	require.Equal(t, Color{0.2, 0.2, 0.2}, red.SpecularColor)
+	require.Equal(t, 25.5, red.ShininessExponent)
+
+	stack := scene.AnimationStacks[0]
+	stop, ok := stack.Property("LocalStop")
+	require.True(t, ok)
+	require.Equal(t, PropertyTime, stop.Kind)
+	require.Equal(t, time.Second, stop.Value)
+}
+
+const templateScene = `; FBX 7.4.0 project file
+Definitions:  {
+	ObjectType: "Model" {
+		PropertyTemplate: "FbxNode" {
+			Properties70:  {
+				P: "Lcl Scaling", "Lcl Scaling", "", "A",2,2,2
+				P: "Visibility", "Visibility", "", "A",1
+			}
+		}
+	}
+	ObjectType: "NodeAttribute" {
+		PropertyTemplate: "FbxCamera" {
+			Properties70:  {
+				P: "FocalLength", "Number", "", "A",50
+			}
+		}
+		PropertyTemplate: "FbxLight" {
+			Properties70:  {
+				P: "Intensity", "Number", "", "A",40
+				P: "CastShadows", "bool", "", "",0
+			}
+		}
+	}
+}
+Objects:  {
+	NodeAttribute: 210, "NodeAttribute::Lamp", "Light" {
+		TypeFlags: "Light"
+	}
+	Model: 200, "Model::Lamp", "Light" {
+		Properties70:  {
+			P: "Lcl Translation", "Lcl Translation", "", "A",1,0,0
+		}
+	}
+}
+Connections:  {
+	C: "OO",200,0
+	C: "OO",210,200
+}
+`
+
+func TestTemplateDefaults(t *testing.T) {
+	scene, err := Load(strings.NewReader(templateScene))
+	require.Nil(t, err)
+	lamp := scene.Lights()[0]
+	require.Equal(t, floatgeom.Point3{2, 2, 2}, getLocalScaling(lamp))
+	require.Equal(t, floatgeom.Point3{3, 2, 2}, lamp.GlobalTransform().TransformPoint(floatgeom.Point3{1, 1, 1}))
+	// The light attribute takes the light template, not the camera's
+	require.Equal(t, 40.0, lamp.Intensity)
+	require.False(t, lamp.CastShadows)
+	attr := lamp.NodeAttribute().(*NodeAttribute)
+	_, ok := attr.Property("FocalLength")
+	require.False(t, ok)
+	castShadows, ok := attr.Property("CastShadows")
+	require.True(t, ok)
+	require.Equal(t, PropertyBool, castShadows.Kind)
+	require.Equal(t, false, castShadows.Value)
+
+	props := lamp.Properties()
+	require.Len(t, props, 3)
+	require.True(t, props[2].FromTemplate)
+	require.Equal(t, "Visibility", props[2].Name)
+	v, ok := props[2].Float()
+	require.True(t, ok)
+	require.Equal(t, 1.0, v)
+}
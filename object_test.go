@@ -0,0 +1,94 @@
+package ofbx
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSceneGraph(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	root := scene.RootNode
+	require.Nil(t, root.Parent())
+	require.Len(t, root.Children(), 2)
+	require.Equal(t, scene.Meshes[0], root.Children()[0])
+	require.Equal(t, Obj(root), scene.Meshes[0].Parent())
+
+	bone := root.FindByName("Bone")
+	require.NotNil(t, bone)
+	require.Equal(t, LIMB_NODE, bone.Type())
+	require.Equal(t, root.FindByName("Model::Root"), bone.Parent())
+	require.Empty(t, bone.Children())
+	require.Nil(t, root.FindByName("Missing"))
+
+	names := []string{}
+	root.Walk(func(o Obj) bool {
+		names = append(names, o.Name())
+		return true
+	})
+	require.Equal(t, []string{"Model::Cube", "Model::Root", "Model::Bone"}, names)
+
+	names = names[:0]
+	root.Walk(func(o Obj) bool {
+		names = append(names, o.Name())
+		return o.Type() != LIMB_NODE
+	})
+	require.Equal(t, []string{"Model::Cube", "Model::Root"}, names)
+}
+
+func TestGlobalTransform(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	mesh := scene.Meshes[0]
+	require.Equal(t, mesh.LocalTransform(), mesh.GlobalTransform())
+	require.Equal(t, IdentityMatrix(), mesh.GeometricTransform())
+
+	bone := scene.RootNode.FindByName("Bone")
+	global := bone.GlobalTransform().Elements()
+	require.Equal(t, []float64{0, 3, 0}, global[12:15])
+	require.Equal(t, 1.0, global[0])
+
+	stack := scene.AnimationStacks[0]
+	animated := bone.GlobalTransformAt(stack, time.Second).Elements()
+	require.Equal(t, []float64{0, 3, 0}, animated[12:15])
+	require.InDelta(t, math.Cos(90.5*math.Pi/180), animated[0], 1e-4)
+	require.InDelta(t, math.Sin(90.5*math.Pi/180), animated[1], 1e-4)
+	// The parent isn't animated
+	require.Equal(t, bone.Parent().GlobalTransform(), bone.Parent().GlobalTransformAt(stack, time.Second))
+}
+
+func TestBaseName(t *testing.T) {
+	require.Equal(t, "Cube", BaseName("Cube\x00\x01Model"))
+	require.Equal(t, "Cube", BaseName("Model::Cube"))
+	require.Equal(t, "Cube", BaseName("Cube"))
+}
+
+const geometricScene = `; FBX 7.4.0 project file
+Objects:  {
+	Model: 100, "Model::Offset", "Mesh" {
+		Properties70:  {
+			P: "Lcl Translation", "Lcl Translation", "", "A",0,0,5
+			P: "GeometricTranslation", "Vector3D", "Vector", "",1,0,0
+			P: "GeometricRotation", "Vector3D", "Vector", "",0,0,90
+			P: "GeometricScaling", "Vector3D", "Vector", "",2,1,1
+		}
+	}
+}
+Connections:  {
+	C: "OO",100,0
+}
+`
+
+func TestGeometricTransform(t *testing.T) {
+	scene, err := Load(strings.NewReader(geometricScene))
+	require.Nil(t, err)
+	mesh := scene.Meshes[0]
+	// Geometry is scaled, then rotated, then translated, and then moved by the mesh
+	geometric := mesh.GeometricTransform()
+	requirePoint3InDelta(t, floatgeom.Point3{1, 2, 0}, geometric.TransformPoint(floatgeom.Point3{1, 0, 0}), 1e-9)
+	world := mesh.GlobalTransform().Mul(geometric)
+	requirePoint3InDelta(t, floatgeom.Point3{1, 2, 5}, world.TransformPoint(floatgeom.Point3{1, 0, 0}), 1e-9)
+}
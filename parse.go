@@ -5,42 +5,29 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"time"
 
 	"github.com/oakmound/oak/v2/alg/floatgeom"
 	"github.com/pkg/errors"
 )
 
-func parseTemplates(root *Element) {
+// parseTemplates collects the PropertyTemplates of the Definitions section by the
+// object type they are for
+func parseTemplates(root *Element, scene *Scene) {
+	scene.templates = make(map[string][]*Element)
 	defs := findChildren(root, "Definitions")
-	if defs == nil {
+	if len(defs) == 0 {
 		return
 	}
-
-	templates := make(map[string]*Element)
-	defs = defs[0].Children
-	for _, def := range defs {
-		if def.ID.String() == "ObjectType" {
-			prop1 := def.getProperty(0).value
-			prop1Data, err := ioutil.ReadAll(prop1)
-			if err != nil && err != io.EOF {
-				//fmt.Println(err)
-				continue
-			}
-			subdefs := def.Children
-			for _, subdef := range subdefs {
-				if subdef.ID.String() == "PropertyTemplate" {
-					prop2 := subdef.getProperty(0).value
-					prop2Data, err := ioutil.ReadAll(prop2)
-					if err != nil && err != io.EOF {
-						//fmt.Println(err)
-						continue
-					}
-					templates[string(prop1Data)+string(prop2Data)] = subdef
-				}
+	for _, def := range defs[0].Children {
+		if def.ID.String() != "ObjectType" || def.getProperty(0) == nil {
+			continue
+		}
+		typ := def.getProperty(0).value.String()
+		for _, subdef := range def.Children {
+			if subdef.ID.String() == "PropertyTemplate" && subdef.getProperty(0) != nil {
+				scene.templates[typ] = append(scene.templates[typ], subdef)
 			}
-
 		}
 	}
 }
@@ -128,6 +115,8 @@ func parseArrayRawInt(property *Property) ([]int, error) {
 	if property.Type == 'd' || property.Type == 'f' {
 		return nil, errors.New("Invalid type, expected i or l")
 	}
+	// Arrays may have been read before
+	property.value.Seek(0, io.SeekStart)
 	if property.Encoding == 0 {
 		return parseArrayRawIntEnd(property.value, property.Count, property.Type.Size()), nil
 	} else if property.Encoding == 1 {
@@ -164,6 +153,8 @@ func parseArrayRawInt64(property *Property) ([]int64, error) {
 	if property.Type == 'd' || property.Type == 'f' {
 		return nil, errors.New("Invalid type, expected i or l")
 	}
+	// Arrays may have been read before
+	property.value.Seek(0, io.SeekStart)
 	if property.Encoding == 0 {
 		return parseArrayRawInt64End(property.value, property.Count, property.Type.Size()), nil
 	} else if property.Encoding == 1 {
@@ -194,8 +185,20 @@ func parseArrayRawInt64End(r io.Reader, ln int, elemSize int) []int64 {
 
 func parseArrayRawFloat32(property *Property) ([]float32, error) {
 	if property.Type == 'i' || property.Type == 'l' {
-		return nil, errors.New("Invalid type, expected d or f")
+		// ASCII files don't distinguish 1 from 1.0, so a float array
+		// can be read in as integers
+		i64s, err := parseArrayRawInt64(property)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]float32, len(i64s))
+		for i, v := range i64s {
+			out[i] = float32(v)
+		}
+		return out, nil
 	}
+	// Arrays may have been read before
+	property.value.Seek(0, io.SeekStart)
 	if property.Encoding == 0 {
 		return parseArrayRawFloat32End(property.value, property.Count, property.Type.Size()), nil
 	} else if property.Encoding == 1 {
@@ -226,8 +229,18 @@ func parseArrayRawFloat32End(r io.Reader, ln int, elemSize int) []float32 {
 
 func parseArrayRawFloat64(property *Property) ([]float64, error) {
 	if property.Type == 'i' || property.Type == 'l' {
-		return nil, errors.New("Invalid type, expected d or f")
+		i64s, err := parseArrayRawInt64(property)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]float64, len(i64s))
+		for i, v := range i64s {
+			out[i] = float64(v)
+		}
+		return out, nil
 	}
+	// Arrays may have been read before
+	property.value.Seek(0, io.SeekStart)
 	if property.Encoding == 0 {
 		return parseArrayRawFloat64End(property.value, property.Count, property.Type.Size()), nil
 	} else if property.Encoding == 1 {
@@ -378,8 +391,37 @@ func parseVertexDataInner(element *Element, name, idxName string) ([]int, Vertex
 
 func parseTexture(scene *Scene, element *Element) *Texture {
 	texture := NewTexture(scene, element)
-	assignSingleChildProperty(element, "FileName", &texture.filename)
-	assignSingleChildProperty(element, "RelativeFilename", &texture.relativeFilename)
+	if prop := findSingleChildProperty(element, "FileName"); prop != nil {
+		texture.Filename = prop.value.String()
+	}
+	if prop := findSingleChildProperty(element, "RelativeFilename"); prop != nil {
+		texture.RelativeFilename = prop.value.String()
+	}
+	if uvSet, ok := texture.Property("UVSet"); ok && uvSet.Kind == PropertyString {
+		texture.UVSet = uvSet.Value.(string)
+	}
+	// Older files hold the UV offset and tiling outside of Properties70
+	if uv := findChildProperty(element, "ModelUVTranslation"); len(uv) == 2 {
+		texture.Translation = floatgeom.Point3{uv[0].number(), uv[1].number(), 0}
+	}
+	if uv := findChildProperty(element, "ModelUVScaling"); len(uv) == 2 {
+		texture.Scaling = floatgeom.Point3{uv[0].number(), uv[1].number(), 1}
+	}
+	texture.Translation = resolveVec3Property(texture, "Translation", texture.Translation)
+	texture.Rotation = resolveVec3Property(texture, "Rotation", texture.Rotation)
+	texture.Scaling = resolveVec3Property(texture, "Scaling", texture.Scaling)
+	texture.WrapModeU = WrapMode(resolveEnumProperty(texture, "WrapModeU", int(texture.WrapModeU)))
+	texture.WrapModeV = WrapMode(resolveEnumProperty(texture, "WrapModeV", int(texture.WrapModeV)))
+	texture.UseMipMap = resolveEnumProperty(texture, "UseMipMap", 0) != 0
+	texture.AlphaSource = AlphaSource(resolveEnumProperty(texture, "AlphaSource", int(texture.AlphaSource)))
+	texture.Alpha = resolveNumberProperty(texture, "Texture_Alpha", texture.Alpha)
+	texture.Premultiply = resolveEnumProperty(texture, "PremultiplyAlpha", 1) != 0
+	texture.BlendMode = TextureBlendMode(resolveEnumProperty(texture, "CurrentTextureBlendMode", int(texture.BlendMode)))
+	if crop := findChildProperty(element, "Cropping"); len(crop) == 4 {
+		for i, c := range crop {
+			texture.Cropping[i] = int(c.integer())
+		}
+	}
 	return texture
 }
 
@@ -399,59 +441,37 @@ func parseMesh(scene *Scene, element *Element) (*Mesh, error) {
 
 func parseMaterial(scene *Scene, element *Element) *Material {
 	material := NewMaterial(scene, element)
-	elems := findChildren(element, "Properties70")
 	material.DiffuseColor = Color{1, 1, 1}
-	if len(elems) == 0 {
-		return material
-	}
-	elems = elems[0].Children
-	// Todo: reflection / struct tags for these types of values
-	for _, elem := range elems {
-		if elem.getProperty(0) == nil {
-			continue
-		}
-		v := elem.getProperty(0).value.String()
-		// Commented out cases are things I (200sc) think might exist
-		// but haven't seen
-		switch v {
-		case "EmissiveColor":
-			material.EmissiveColor.R = float32(elem.getProperty(4).value.toDouble())
-			material.EmissiveColor.G = float32(elem.getProperty(5).value.toDouble())
-			material.EmissiveColor.B = float32(elem.getProperty(6).value.toDouble())
-		case "AmbientColor":
-			material.AmbientColor.R = float32(elem.getProperty(4).value.toDouble())
-			material.AmbientColor.G = float32(elem.getProperty(5).value.toDouble())
-			material.AmbientColor.B = float32(elem.getProperty(6).value.toDouble())
-		case "DiffuseColor":
-			material.DiffuseColor.R = float32(elem.getProperty(4).value.toDouble())
-			material.DiffuseColor.G = float32(elem.getProperty(5).value.toDouble())
-			material.DiffuseColor.B = float32(elem.getProperty(6).value.toDouble())
-		case "TransparentColor":
-			material.TransparentColor.R = float32(elem.getProperty(4).value.toDouble())
-			material.TransparentColor.G = float32(elem.getProperty(5).value.toDouble())
-			material.TransparentColor.B = float32(elem.getProperty(6).value.toDouble())
-		case "SpecularColor":
-			material.SpecularColor.R = float32(elem.getProperty(4).value.toDouble())
-			material.SpecularColor.G = float32(elem.getProperty(5).value.toDouble())
-			material.SpecularColor.B = float32(elem.getProperty(6).value.toDouble())
-		case "ReflectionColor":
-			material.ReflectionColor.R = float32(elem.getProperty(4).value.toDouble())
-			material.ReflectionColor.G = float32(elem.getProperty(5).value.toDouble())
-			material.ReflectionColor.B = float32(elem.getProperty(6).value.toDouble())
-		case "EmissiveFactor":
-			material.EmissiveFactor = elem.getProperty(4).value.toDouble()
-		// case "AmbientFactor":
-		case "DiffuseFactor":
-			material.DiffuseFactor = elem.getProperty(4).value.toDouble()
-		// case "TransparentFactor":
-		case "SpecularFactor":
-			material.SpecularFactor = elem.getProperty(4).value.toDouble()
-		case "ReflectionFactor":
-			material.ReflectionFactor = elem.getProperty(4).value.toDouble()
-		case "Shininess":
-			material.Shininess = elem.getProperty(4).value.toDouble()
-		case "ShininessExponent":
-			material.ShininessExponent = elem.getProperty(4).value.toDouble()
+	colors := map[string]*Color{
+		"EmissiveColor":    &material.EmissiveColor,
+		"AmbientColor":     &material.AmbientColor,
+		"DiffuseColor":     &material.DiffuseColor,
+		"TransparentColor": &material.TransparentColor,
+		"SpecularColor":    &material.SpecularColor,
+		"ReflectionColor":  &material.ReflectionColor,
+	}
+	// Commented out factors are things I (200sc) think might exist
+	// but haven't seen
+	factors := map[string]*float64{
+		"EmissiveFactor": &material.EmissiveFactor,
+		// "AmbientFactor":
+		"DiffuseFactor": &material.DiffuseFactor,
+		// "TransparentFactor":
+		"SpecularFactor":    &material.SpecularFactor,
+		"ReflectionFactor":  &material.ReflectionFactor,
+		"Shininess":         &material.Shininess,
+		"ShininessExponent": &material.ShininessExponent,
+	}
+	// Properties include the material template's defaults for any the material doesn't set
+	for _, prop := range material.Properties() {
+		if c, ok := colors[prop.Name]; ok {
+			if v, ok := prop.Vec3(); ok {
+				*c = Color{float32(v.X()), float32(v.Y()), float32(v.Z())}
+			}
+		} else if f, ok := factors[prop.Name]; ok {
+			if v, ok := prop.Float(); ok {
+				*f = v
+			}
 		}
 	}
 	return material
@@ -467,7 +487,7 @@ func parseAnimationCurve(scene *Scene, element *Element) (*AnimationCurve, error
 		}
 	}
 	if attrData := findSingleChildProperty(element, "KeyAttrDataFloat"); attrData != nil {
-		curve.AttrData, err = attrData.getValuesF32()
+		curve.AttrData, err = parseKeyAttrData(attrData, curve.AttrFlags)
 		if err != nil {
 			return nil, errors.Wrap(err, "Invalid animation curve: attrFlags error")
 		}
@@ -497,6 +517,7 @@ func parseAnimationCurve(scene *Scene, element *Element) (*AnimationCurve, error
 	if len(curve.Times) != len(curve.Values) {
 		return nil, errors.New("Invalid animation curve: len error")
 	}
+	curve.decodeKeys()
 	return curve, nil
 }
 
@@ -538,7 +559,7 @@ func parseConnection(root *Element, scene *Scene) (bool, error) {
 
 func parseTakes(scene *Scene) (bool, error) {
 	takes := findChildren(scene.RootElement, "Takes")
-	if takes == nil {
+	if len(takes) == 0 {
 		return true, nil
 	}
 
@@ -672,6 +693,11 @@ func parseObjects(root *Element, scene *Scene) (bool, error) {
 				if err != nil {
 					return false, err
 				}
+			} else if lastProp != nil && lastProp.value.String() == "Shape" {
+				obj, err = parseShape(scene, elem)
+				if err != nil {
+					return false, err
+				}
 			}
 		case "Material":
 			obj = parseMaterial(scene, elem)
@@ -699,6 +725,13 @@ func parseObjects(root *Element, scene *Scene) (bool, error) {
 					}
 				} else if v == "Skin" {
 					obj = NewSkin(scene, elem)
+				} else if v == "BlendShape" {
+					obj = NewBlendShape(scene, elem)
+				} else if v == "BlendShapeChannel" {
+					obj, err = parseBlendShapeChannel(scene, elem)
+					if err != nil {
+						return false, err
+					}
 				}
 			}
 		case "NodeAttribute":
@@ -722,12 +755,25 @@ func parseObjects(root *Element, scene *Scene) (bool, error) {
 					if err != nil {
 						return false, err
 					}
+				} else if v == "Camera" {
+					obj = NewCamera(scene, elem)
+				} else if v == "Light" {
+					obj = NewLight(scene, elem)
 				} else if v == "Null" || v == "Root" {
 					obj = NewNode(scene, elem, NULL_NODE)
 				}
 			}
 		case "Texture":
 			obj = parseTexture(scene, elem)
+		case "Pose":
+			pose, err := parsePose(scene, elem)
+			if err != nil {
+				return false, err
+			}
+			if pose.IsBindPose() {
+				scene.BindPoses = append(scene.BindPoses, pose)
+			}
+			obj = pose
 		}
 
 		scene.ObjectMap[id] = obj
@@ -747,6 +793,11 @@ func parseObjects(root *Element, scene *Scene) (bool, error) {
 
 		ctyp := child.Type()
 
+		if con.typ == ObjectConn && child.IsNode() && parent.IsNode() && child.Parent() == nil {
+			child.setParent(parent)
+			parent.addChild(child)
+		}
+
 		switch ctyp {
 		case NODE_ATTRIBUTE:
 			if parent.NodeAttribute() != nil {
@@ -754,7 +805,8 @@ func parseObjects(root *Element, scene *Scene) (bool, error) {
 			}
 			parent.SetNodeAttribute(child) //previously asserted that the child was a nodeattribute
 		case ANIMATION_CURVE_NODE:
-			if parent.IsNode() {
+			// Curve nodes animate the property of whatever they are connected to
+			if parent.IsNode() || con.typ == PropConn {
 				node := child.(*AnimationCurveNode)
 				node.Bone = parent
 				node.BoneLinkProp = con.property
@@ -785,25 +837,31 @@ func parseObjects(root *Element, scene *Scene) (bool, error) {
 			}
 		case MATERIAL:
 			mat := parent.(*Material)
-			if ctyp == TEXTURE {
-				ttyp := TextureCOUNT
-				if con.property == "NormalMap" {
-					ttyp = NORMAL
-				} else if con.property == "DiffuseColor" {
-					ttyp = DIFFUSE
-				}
-				if ttyp == TextureCOUNT {
-					break
-				}
-				if mat.Textures[ttyp] != nil {
-					break
-				}
-				mat.Textures[ttyp] = child.(*Texture)
+			if ctyp == TEXTURE && con.typ == PropConn {
+				mat.addTexture(con.property, child.(*Texture))
 			}
 		case GEOMETRY:
 			geom := parent.(*Geometry)
 			if ctyp == SKIN {
 				geom.Skin = child.(*Skin)
+			} else if ctyp == BLEND_SHAPE {
+				geom.BlendShapes = append(geom.BlendShapes, child.(*BlendShape))
+			}
+		case BLEND_SHAPE:
+			if ctyp == BLEND_SHAPE_CHANNEL {
+				bs := parent.(*BlendShape)
+				channel := child.(*BlendShapeChannel)
+				bs.Channels = append(bs.Channels, channel)
+				channel.BlendShape = bs
+			}
+		case BLEND_SHAPE_CHANNEL:
+			if ctyp == SHAPE {
+				channel := parent.(*BlendShapeChannel)
+				channel.Shapes = append(channel.Shapes, child.(*Shape))
+			}
+		case CAMERA:
+			if con.property == "LookAtProperty" && child.IsNode() {
+				parent.(*Camera).Target = child
 			}
 		case CLUSTER:
 			cluster := parent.(*Cluster)
@@ -823,18 +881,7 @@ func parseObjects(root *Element, scene *Scene) (bool, error) {
 		case ANIMATION_CURVE_NODE:
 			node := parent.(*AnimationCurveNode)
 			if ctyp == ANIMATION_CURVE {
-				if node.Curves[0].Curve == nil {
-					node.Curves[0].connection = &con
-					node.Curves[0].Curve = child.(*AnimationCurve)
-				} else if node.Curves[1].Curve == nil {
-					node.Curves[1].connection = &con
-					node.Curves[1].Curve = child.(*AnimationCurve)
-				} else if node.Curves[2].Curve == nil {
-					node.Curves[2].connection = &con
-					node.Curves[2].Curve = child.(*AnimationCurve)
-				} else {
-					return false, errors.New("Invalid animation node")
-				}
+				node.addCurve(child.(*AnimationCurve), &con)
 			}
 		}
 	}
@@ -0,0 +1,153 @@
+package ofbx
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Pose is a set of node transforms, such as the bind pose skins were created in
+type Pose struct {
+	Object
+	// PoseType is "BindPose" or "RestPose"
+	PoseType string
+	Nodes    []PoseNode
+}
+
+// PoseNode is a node's transform in a Pose. Bind poses hold global transforms.
+type PoseNode struct {
+	NodeID uint64
+	// Node is the node NodeID refers to, or nil if there is no such node
+	Node   Obj
+	Matrix Matrix
+}
+
+// NewPose creates a new Pose with no nodes
+func NewPose(scene *Scene, element *Element) *Pose {
+	return &Pose{Object: *NewObject(scene, element)}
+}
+
+// Type returns POSE
+func (p *Pose) Type() Type {
+	return POSE
+}
+
+// IsBindPose returns whether the pose holds the transforms nodes were bound to skins in
+func (p *Pose) IsBindPose() bool {
+	return p.PoseType == "BindPose"
+}
+
+// Matrix returns the pose's transform for the given node
+func (p *Pose) Matrix(node Obj) (Matrix, bool) {
+	for _, pn := range p.Nodes {
+		if pn.NodeID == node.ID() {
+			return pn.Matrix, true
+		}
+	}
+	return Matrix{}, false
+}
+
+// String pretty prints the Pose
+func (p *Pose) String() string {
+	return p.stringPrefix("")
+}
+
+func (p *Pose) stringPrefix(prefix string) string {
+	s := prefix + "Pose: " + p.PoseType + "\n"
+	for _, pn := range p.Nodes {
+		s += prefix + "\t" + fmt.Sprintf("node=%d matrix=%v", pn.NodeID, pn.Matrix.m) + "\n"
+	}
+	return s
+}
+
+// postProcess resolves the pose's node ids, which are not stored as connections
+func (p *Pose) postProcess() bool {
+	for i, pn := range p.Nodes {
+		p.Nodes[i].Node = p.scene.ObjectMap[pn.NodeID]
+	}
+	return true
+}
+
+func parsePose(scene *Scene, element *Element) (*Pose, error) {
+	pose := NewPose(scene, element)
+	if typ := findSingleChildProperty(element, "Type"); typ != nil {
+		pose.PoseType = typ.value.String()
+	} else if class := element.getProperty(2); class != nil {
+		pose.PoseType = class.value.String()
+	}
+	for _, child := range element.Children {
+		if child.ID.String() != "PoseNode" {
+			continue
+		}
+		node := findSingleChildProperty(child, "Node")
+		matrix := findSingleChildProperty(child, "Matrix")
+		if node == nil || matrix == nil {
+			return nil, errors.New("Invalid pose node")
+		}
+		mx, err := parseArrayRawFloat64(matrix)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to parse pose matrix")
+		}
+		pn := PoseNode{NodeID: node.value.touint64()}
+		if pn.Matrix, err = matrixFromSlice(mx); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse pose matrix")
+		}
+		pose.Nodes = append(pose.Nodes, pn)
+	}
+	return pose, nil
+}
+
+// BindMatrix returns the global transform node had when it was bound, from the first
+// of the scene's bind poses which has it
+func (s *Scene) BindMatrix(node Obj) (Matrix, bool) {
+	for _, pose := range s.BindPoses {
+		if m, ok := pose.Matrix(node); ok {
+			return m, true
+		}
+	}
+	return Matrix{}, false
+}
+
+// RestoreBindPose rebuilds the bind transforms of every cluster in the scene whose
+// TransformLink or Transform is missing, or differs from the scene's bind poses by
+// more than tolerance in any element. Missing transforms without a bind pose entry are
+// taken from the node's static global transform. It returns the number of clusters changed.
+func (s *Scene) RestoreBindPose(tolerance float64) int {
+	changed := 0
+	for _, mesh := range s.Meshes {
+		if mesh.Geometry == nil || mesh.Geometry.Skin == nil {
+			continue
+		}
+		for _, c := range mesh.Geometry.Skin.Clusters {
+			fixed := false
+			if c.Link != nil {
+				fixed = s.restoreBindMatrix(&c.TransformLink, c.Link, tolerance)
+			}
+			if s.restoreBindMatrix(&c.Transform, mesh, tolerance) {
+				fixed = true
+			}
+			if fixed {
+				changed++
+			}
+		}
+	}
+	return changed
+}
+
+// restoreBindMatrix sets m to node's bind matrix if they differ, returning whether m changed
+func (s *Scene) restoreBindMatrix(m *Matrix, node Obj, tolerance float64) bool {
+	bind, ok := s.BindMatrix(node)
+	if !ok {
+		if *m != (Matrix{}) {
+			return false
+		}
+		bind = node.GlobalTransform()
+	}
+	for i := range bind.m {
+		if d := bind.m[i] - m.m[i]; d > tolerance || d < -tolerance {
+			*m = bind
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package ofbx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindPose(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	require.Len(t, scene.BindPoses, 1)
+	pose := scene.BindPoses[0]
+	require.Equal(t, POSE, pose.Type())
+	require.True(t, pose.IsBindPose())
+	require.Len(t, pose.Nodes, 3)
+	require.Equal(t, Obj(scene.Meshes[0]), pose.Nodes[0].Node)
+
+	bone := scene.RootNode.FindByName("Bone")
+	m, ok := scene.BindMatrix(bone)
+	require.True(t, ok)
+	require.Equal(t, 3.0, m.At(1, 3))
+	_, ok = pose.Matrix(scene.RootNode)
+	require.False(t, ok)
+
+	// The fixture's cluster Transforms are offset from the mesh's bind matrix
+	clusters := scene.Meshes[0].Geometry.Skin.Clusters
+	require.Equal(t, -3.0, clusters[1].Transform.At(1, 3))
+	require.Equal(t, 2, scene.RestoreBindPose(1e-6))
+	require.Equal(t, IdentityMatrix(), clusters[1].Transform)
+	require.Equal(t, 0, scene.RestoreBindPose(1e-6))
+
+	clusters[1].TransformLink = Matrix{}
+	clusters[0].TransformLink.Set(1, 3, 5)
+	require.Equal(t, 2, scene.RestoreBindPose(1e-6))
+	require.Equal(t, m, clusters[1].TransformLink)
+	require.Equal(t, 1.0, clusters[0].TransformLink.At(1, 3))
+
+	// Without a bind pose only missing transforms are rebuilt, from the static pose
+	scene.BindPoses = nil
+	clusters[0].TransformLink.Set(1, 3, 5)
+	clusters[1].TransformLink = Matrix{}
+	require.Equal(t, 1, scene.RestoreBindPose(1e-6))
+	require.Equal(t, bone.GlobalTransform(), clusters[1].TransformLink)
+	require.Equal(t, 5.0, clusters[0].TransformLink.At(1, 3))
+}
@@ -1,8 +1,12 @@
 package ofbx
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
+
+	"github.com/pkg/errors"
 )
 
 // PropertyType is a mapping of letter to data type
@@ -107,6 +111,41 @@ func (pt PropertyType) IsArray() bool {
 	return false
 }
 
+// CompressedLength returns the length in bytes of an array property's data as it is stored
+func (p *Property) CompressedLength() int {
+	if p.compressedLength != 0 {
+		return int(p.compressedLength)
+	}
+	return len(p.value.bytes())
+}
+
+// Int64s returns the values of an integer, bool or byte array property
+func (p *Property) Int64s() ([]int64, error) {
+	switch p.Type {
+	case ArrayINT, ArrayLONG:
+		return parseArrayRawInt64(p)
+	case ArrayBOOL, ArrayBYTE:
+		data, err := arrayBytes(p)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]int64, len(data))
+		for i, b := range data {
+			out[i] = int64(b)
+		}
+		return out, nil
+	}
+	return nil, errors.New("Not an integer array: " + string(p.Type))
+}
+
+// Float64s returns the values of a float, double, integer or long array property
+func (p *Property) Float64s() ([]float64, error) {
+	if !p.Type.IsArray() || p.Type == ArrayBOOL || p.Type == ArrayBYTE {
+		return nil, errors.New("Not a numeric array: " + string(p.Type))
+	}
+	return parseArrayRawFloat64(p)
+}
+
 // A Property is template class is used to ensure that the data of a FbxObject is strongly typed
 type Property struct {
 	Count            int
@@ -116,6 +155,33 @@ type Property struct {
 	compressedLength uint32
 }
 
+// NewStringProperty creates a STRING Property. Object names in binary files are
+// written as Name\x00\x01Class, e.g. "Bone\x00\x01Model".
+func NewStringProperty(s string) *Property {
+	return &Property{Type: STRING, value: NewDataView(s)}
+}
+
+// NewLongProperty creates a LONG Property, as used for object ids
+func NewLongProperty(i int64) *Property {
+	return newScalarProperty(LONG, i)
+}
+
+// NewIntegerProperty creates an INTEGER Property
+func NewIntegerProperty(i int32) *Property {
+	return newScalarProperty(INTEGER, i)
+}
+
+// NewDoubleProperty creates a DOUBLE Property
+func NewDoubleProperty(f float64) *Property {
+	return newScalarProperty(DOUBLE, f)
+}
+
+func newScalarProperty(typ PropertyType, v interface{}) *Property {
+	buff := bytes.NewBuffer([]byte{})
+	binary.Write(buff, binary.LittleEndian, v)
+	return &Property{Type: typ, value: BufferDataView(buff)}
+}
+
 func (p *Property) getValuesF32() ([]float32, error) {
 	return parseArrayRawFloat32(p)
 }
@@ -134,15 +200,6 @@ func findChildren(element *Element, id string) []*Element {
 	return []*Element{}
 }
 
-func assignSingleChildProperty(element *Element, id string, dv **DataView) bool {
-	prop := findSingleChildProperty(element, id)
-	if prop != nil {
-		*dv = prop.value
-		return true
-	}
-	return false
-}
-
 func findSingleChildProperty(element *Element, id string) *Property {
 	iterables := element.Children
 	for idx, val := range iterables {
@@ -165,19 +222,13 @@ func findChildProperty(element *Element, id string) []*Property {
 	return nil
 }
 
+// resolveProperty returns obj's Properties70 entry with the given name, or its
+// Definitions template's if obj doesn't have it
 func resolveProperty(obj Obj, name string) *Element {
-	elems := findChildren(obj.Element(), "Properties70")
-	if elems == nil {
-		return nil
+	if elem := findProperty70(obj.Element(), name); elem != nil {
+		return elem
 	}
-
-	elems = elems[0].Children
-	for _, elem := range elems {
-		if prop := elem.getProperty(0); prop != nil && prop.value.String() == name {
-			return elem
-		}
-	}
-	return nil
+	return findProperty70(obj.Scene().template(obj.Element()), name)
 }
 
 func isString(prop *Property) bool {
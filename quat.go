@@ -0,0 +1,156 @@
+package ofbx
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/v2/alg"
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// Quat is a rotation quaternion
+type Quat struct {
+	X, Y, Z, W float64
+}
+
+// IdentityQuat returns a Quat which rotates nothing
+func IdentityQuat() Quat {
+	return Quat{0, 0, 0, 1}
+}
+
+// QuatFromAxisAngle creates a rotation of angle radians around an axis
+func QuatFromAxisAngle(axis floatgeom.Point3, angle float64) Quat {
+	axis = axis.Normalize()
+	s := math.Sin(angle / 2)
+	return Quat{axis.X() * s, axis.Y() * s, axis.Z() * s, math.Cos(angle / 2)}
+}
+
+// QuatFromEuler converts euler angles, in degrees, applied in the given order
+// to a Quat. Unknown orders are treated as EulerXYZ.
+func QuatFromEuler(euler floatgeom.Point3, order RotationOrder) Quat {
+	qx := QuatFromAxisAngle(floatgeom.Point3{1, 0, 0}, euler.X()*alg.DegToRad)
+	qy := QuatFromAxisAngle(floatgeom.Point3{0, 1, 0}, euler.Y()*alg.DegToRad)
+	qz := QuatFromAxisAngle(floatgeom.Point3{0, 0, 1}, euler.Z()*alg.DegToRad)
+	switch order {
+	case EulerXZY:
+		return qy.Mul(qz).Mul(qx)
+	case EulerYXZ:
+		return qz.Mul(qx).Mul(qy)
+	case EulerYZX:
+		return qx.Mul(qz).Mul(qy)
+	case EulerZXY:
+		return qy.Mul(qx).Mul(qz)
+	case EulerZYX:
+		return qx.Mul(qy).Mul(qz)
+	}
+	return qz.Mul(qy).Mul(qx)
+}
+
+// QuatFromMatrix returns the rotation of a transformation matrix, ignoring its scale
+func QuatFromMatrix(m Matrix) Quat {
+	_, q, _ := m.Decompose()
+	return q
+}
+
+// quatFromRotation converts a column major 3x3 rotation matrix to a unit quaternion
+func quatFromRotation(m [9]float64) Quat {
+	m00, m10, m20 := m[0], m[1], m[2]
+	m01, m11, m21 := m[3], m[4], m[5]
+	m02, m12, m22 := m[6], m[7], m[8]
+
+	var q Quat
+	trace := m00 + m11 + m22
+	switch {
+	case trace > 0:
+		s := 0.5 / math.Sqrt(trace+1)
+		q = Quat{(m21 - m12) * s, (m02 - m20) * s, (m10 - m01) * s, 0.25 / s}
+	case m00 > m11 && m00 > m22:
+		s := 2 * math.Sqrt(1+m00-m11-m22)
+		q = Quat{0.25 * s, (m01 + m10) / s, (m02 + m20) / s, (m21 - m12) / s}
+	case m11 > m22:
+		s := 2 * math.Sqrt(1+m11-m00-m22)
+		q = Quat{(m01 + m10) / s, 0.25 * s, (m12 + m21) / s, (m02 - m20) / s}
+	default:
+		s := 2 * math.Sqrt(1+m22-m00-m11)
+		q = Quat{(m02 + m20) / s, (m12 + m21) / s, 0.25 * s, (m10 - m01) / s}
+	}
+	return q.Normalize()
+}
+
+// Mul returns the rotation of q2 followed by q
+func (q Quat) Mul(q2 Quat) Quat {
+	return Quat{
+		q.W*q2.X + q.X*q2.W + q.Y*q2.Z - q.Z*q2.Y,
+		q.W*q2.Y - q.X*q2.Z + q.Y*q2.W + q.Z*q2.X,
+		q.W*q2.Z + q.X*q2.Y - q.Y*q2.X + q.Z*q2.W,
+		q.W*q2.W - q.X*q2.X - q.Y*q2.Y - q.Z*q2.Z,
+	}
+}
+
+// Dot returns the dot product of two quaternions
+func (q Quat) Dot(q2 Quat) float64 {
+	return q.X*q2.X + q.Y*q2.Y + q.Z*q2.Z + q.W*q2.W
+}
+
+// Length returns the magnitude of the quaternion
+func (q Quat) Length() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+// Normalize returns the quaternion scaled to unit length, or the identity if q has no length
+func (q Quat) Normalize() Quat {
+	l := q.Length()
+	if l == 0 {
+		return IdentityQuat()
+	}
+	return Quat{q.X / l, q.Y / l, q.Z / l, q.W / l}
+}
+
+// Conjugate returns the inverse rotation of a unit quaternion
+func (q Quat) Conjugate() Quat {
+	return Quat{-q.X, -q.Y, -q.Z, q.W}
+}
+
+// Rotate applies the rotation to a point
+func (q Quat) Rotate(p floatgeom.Point3) floatgeom.Point3 {
+	r := q.Mul(Quat{p.X(), p.Y(), p.Z(), 0}).Mul(q.Conjugate())
+	return floatgeom.Point3{r.X, r.Y, r.Z}
+}
+
+// Slerp spherically interpolates from q to q2 by t, taking the shortest path
+func (q Quat) Slerp(q2 Quat, t float64) Quat {
+	dot := q.Dot(q2)
+	if dot < 0 {
+		q2 = Quat{-q2.X, -q2.Y, -q2.Z, -q2.W}
+		dot = -dot
+	}
+	// Nearly identical rotations are linearly interpolated to avoid dividing by zero
+	if dot > 0.9995 {
+		return Quat{
+			q.X + (q2.X-q.X)*t,
+			q.Y + (q2.Y-q.Y)*t,
+			q.Z + (q2.Z-q.Z)*t,
+			q.W + (q2.W-q.W)*t,
+		}.Normalize()
+	}
+	theta := math.Acos(dot)
+	sin := math.Sin(theta)
+	a := math.Sin((1-t)*theta) / sin
+	b := math.Sin(t*theta) / sin
+	return Quat{
+		q.X*a + q2.X*b,
+		q.Y*a + q2.Y*b,
+		q.Z*a + q2.Z*b,
+		q.W*a + q2.W*b,
+	}
+}
+
+// Matrix converts a unit quaternion to a rotation matrix
+func (q Quat) Matrix() Matrix {
+	x, y, z, w := q.X, q.Y, q.Z, q.W
+	return Matrix{[16]float64{
+		1 - 2*(y*y+z*z), 2 * (x*y + z*w), 2 * (x*z - y*w), 0,
+		2 * (x*y - z*w), 1 - 2*(x*x+z*z), 2 * (y*z + x*w), 0,
+		2 * (x*z + y*w), 2 * (y*z - x*w), 1 - 2*(x*x+y*y), 0,
+		0, 0, 0, 1,
+	}}
+}
@@ -0,0 +1,47 @@
+package ofbx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuatFromEuler(t *testing.T) {
+	euler := floatgeom.Point3{30, -45, 100}
+	orders := []RotationOrder{EulerXYZ, EulerXZY, EulerYZX, EulerYXZ, EulerZXY, EulerZYX, SphericXYZ}
+	for _, order := range orders {
+		q := QuatFromEuler(euler, order)
+		require.InDelta(t, 1.0, q.Length(), 1e-9)
+		requireMatrixInDelta(t, order.rotationMatrix(euler), q.Matrix())
+
+		back := QuatFromMatrix(q.Matrix())
+		require.InDelta(t, 1.0, math.Abs(q.Dot(back)), 1e-9, "order %d", order)
+	}
+	requireMatrixInDelta(t, EulerXYZ.rotationMatrix(euler), SphericXYZ.rotationMatrix(euler))
+}
+
+func TestQuatMul(t *testing.T) {
+	a := QuatFromEuler(floatgeom.Point3{10, 20, 30}, EulerXYZ)
+	b := QuatFromEuler(floatgeom.Point3{-40, 5, 60}, EulerZYX)
+	requireMatrixInDelta(t, a.Matrix().Mul(b.Matrix()), a.Mul(b).Matrix())
+	requireMatrixInDelta(t, IdentityMatrix(), a.Mul(a.Conjugate()).Matrix())
+
+	p := QuatFromAxisAngle(floatgeom.Point3{0, 0, 1}, math.Pi/2).Rotate(floatgeom.Point3{1, 0, 0})
+	require.InDelta(t, 0.0, p.X(), 1e-9)
+	require.InDelta(t, 1.0, p.Y(), 1e-9)
+}
+
+func TestQuatSlerp(t *testing.T) {
+	axis := floatgeom.Point3{0, 1, 0}
+	a := QuatFromAxisAngle(axis, 0)
+	b := QuatFromAxisAngle(axis, math.Pi/2)
+	half := a.Slerp(b, 0.5)
+	require.InDelta(t, 1.0, half.Dot(QuatFromAxisAngle(axis, math.Pi/4)), 1e-9)
+	require.Equal(t, a, a.Slerp(b, 0))
+
+	// The negated quaternion is the same rotation, so the shortest path is taken
+	negB := Quat{-b.X, -b.Y, -b.Z, -b.W}
+	require.InDelta(t, 1.0, math.Abs(a.Slerp(negB, 0.5).Dot(half)), 1e-9)
+}
@@ -10,25 +10,22 @@ type RotationOrder int
 
 // A block of rotation order sets
 const (
-	EulerXYZ   RotationOrder = iota
-	EulerXZY   RotationOrder = iota
-	EulerYZX   RotationOrder = iota
-	EulerYXZ   RotationOrder = iota
-	EulerZXY   RotationOrder = iota
-	EulerZYX   RotationOrder = iota
-	SphericXYZ RotationOrder = iota // Currently unsupported. Treated as EulerXYZ.
+	EulerXYZ RotationOrder = iota
+	EulerXZY RotationOrder = iota
+	EulerYZX RotationOrder = iota
+	EulerYXZ RotationOrder = iota
+	EulerZXY RotationOrder = iota
+	EulerZYX RotationOrder = iota
+	// SphericXYZ only changes how the FBX SDK interpolates rotations, they are composed as EulerXYZ
+	SphericXYZ RotationOrder = iota
 )
 
+// rotationMatrix converts euler angles, in degrees, to a rotation matrix. Unknown orders are treated as EulerXYZ.
 func (o RotationOrder) rotationMatrix(euler floatgeom.Point3) Matrix {
 	rx := rotationX(euler.X() * alg.DegToRad)
 	ry := rotationY(euler.Y() * alg.DegToRad)
 	rz := rotationZ(euler.Z() * alg.DegToRad)
 	switch o {
-	default:
-	case SphericXYZ:
-		panic("This should not happen")
-	case EulerXYZ:
-		return rz.Mul(ry).Mul(rx)
 	case EulerXZY:
 		return ry.Mul(rz).Mul(rx)
 	case EulerYXZ:
@@ -40,5 +37,5 @@ func (o RotationOrder) rotationMatrix(euler floatgeom.Point3) Matrix {
 	case EulerZYX:
 		return rx.Mul(ry).Mul(rz)
 	}
-	panic("This shouldn't happen either")
+	return rz.Mul(ry).Mul(rx)
 }
@@ -16,6 +16,9 @@ type Scene struct {
 	AnimationStacks []*AnimationStack
 	Connections     []Connection
 	TakeInfos       []TakeInfo
+	BindPoses       []*Pose
+	// templates are the Definitions' PropertyTemplates by object type, e.g. Model
+	templates map[string][]*Element
 }
 
 func (s *Scene) String() string {
@@ -80,12 +83,11 @@ func (s *Scene) getTakeInfo(name string) *TakeInfo {
 	return nil
 }
 
-// Load tries to load a scene
+// Load tries to load a scene from either a binary or an ASCII FBX file
 func Load(r io.Reader) (*Scene, error) {
 	s := &Scene{}
 	s.ObjectMap = make(map[uint64]Obj)
 	root, err := tokenize(r)
-	// Todo: reimplement text
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +100,7 @@ func Load(r io.Reader) (*Scene, error) {
 	if ok, err := parseTakes(s); !ok {
 		return nil, err
 	}
+	parseTemplates(root, s)
 	if ok, err := parseObjects(root, s); !ok {
 		return nil, err
 	}
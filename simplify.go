@@ -0,0 +1,150 @@
+package ofbx
+
+import (
+	"time"
+)
+
+// Simplify removes keys from the curve which can be dropped without it moving more than
+// tolerance from where it was, measured at each original key and halfway between them.
+// A run of keys with the same value collapses to its first and last keys. It returns
+// the number of keys removed.
+func (ac *AnimationCurve) Simplify(tolerance float32) int {
+	n := len(ac.Times)
+	if n < 3 || len(ac.Values) < n {
+		return 0
+	}
+	hasKeys := len(ac.Keys) == n
+	orig := &AnimationCurve{Times: ac.Times, Values: ac.Values, Keys: ac.Keys}
+	mids := make([]float32, n-1)
+	for i := range mids {
+		mids[i] = orig.Evaluate(midpoint(ac.Times[i], ac.Times[i+1]))
+	}
+
+	kept := []int{0}
+	start := 0
+	for end := 2; end < n; end++ {
+		if !orig.segmentFits(start, end, mids, tolerance) {
+			kept = append(kept, end-1)
+			start = end - 1
+		}
+	}
+	kept = append(kept, n-1)
+	if len(kept) == n {
+		return 0
+	}
+
+	times := make([]time.Duration, len(kept))
+	values := make([]float32, len(kept))
+	var keys []Key
+	if hasKeys {
+		keys = make([]Key, len(kept))
+	}
+	for i, k := range kept {
+		times[i], values[i] = ac.Times[k], ac.Values[k]
+		if hasKeys {
+			if i+1 < len(kept) {
+				keys[i] = orig.mergedKey(k, kept[i+1])
+			} else {
+				keys[i] = ac.Keys[k]
+			}
+		}
+	}
+	ac.Times, ac.Values = times, values
+	if hasKeys {
+		ac.Keys = keys
+		ac.encodeKeys()
+	}
+	return n - len(kept)
+}
+
+// segmentFits returns whether replacing the keys between start and end with a single
+// segment keeps the curve within tolerance of its original values
+func (ac *AnimationCurve) segmentFits(start, end int, mids []float32, tolerance float32) bool {
+	seg := &AnimationCurve{
+		Times:  []time.Duration{ac.Times[start], ac.Times[end]},
+		Values: []float32{ac.Values[start], ac.Values[end]},
+	}
+	if len(ac.Keys) == len(ac.Times) {
+		seg.Keys = []Key{ac.mergedKey(start, end), ac.Keys[end]}
+	}
+	for i := start; i < end; i++ {
+		if i != start && !within(seg.interpolate(0, ac.Times[i]), ac.Values[i], tolerance) {
+			return false
+		}
+		if !within(seg.interpolate(0, midpoint(ac.Times[i], ac.Times[i+1])), mids[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergedKey returns key start's attributes for a segment running to key end. It leaves
+// as key start did and arrives as the key before end did. TCB keys keep their slopes,
+// as the neighbors their slopes came from may be removed.
+func (ac *AnimationCurve) mergedKey(start, end int) Key {
+	k := ac.Keys[start]
+	prev := ac.Keys[end-1]
+	k.NextLeftSlope, k.NextLeftWeight, k.NextLeftVelocity = prev.NextLeftSlope, prev.NextLeftWeight, prev.NextLeftVelocity
+	if k.TangentMode == TangentTCB {
+		k.TangentMode = TangentBreak
+		k.Tension, k.Continuity, k.Bias = 0, 0, 0
+	}
+	return k
+}
+
+// unwrapDegrees returns angles shifted by whole turns so consecutive values are
+// at most half a turn apart
+func unwrapDegrees(angles []float32) []float32 {
+	out := make([]float32, len(angles))
+	copy(out, angles)
+	for i := 1; i < len(out); i++ {
+		for out[i]-out[i-1] > 180 {
+			out[i] -= 360
+		}
+		for out[i]-out[i-1] < -180 {
+			out[i] += 360
+		}
+	}
+	return out
+}
+
+// Simplify simplifies every curve of the stack's layers with the given tolerance, in
+// degrees for rotations. Rotation curves are unwrapped when keys are removed from them,
+// so an angle passing 180 degrees doesn't jump a full turn between keys. It returns
+// the number of keys removed.
+func (as *AnimationStack) Simplify(tolerance float32) int {
+	removed := 0
+	seen := map[*AnimationCurve]bool{}
+	for _, layer := range as.Layers {
+		for _, cn := range layer.CurveNodes {
+			for _, c := range cn.Curves {
+				if c.Curve == nil || seen[c.Curve] {
+					continue
+				}
+				seen[c.Curve] = true
+				if cn.BoneLinkProp != BoneRotate {
+					removed += c.Curve.Simplify(tolerance)
+					continue
+				}
+				// Curves nothing is removed from keep their original angles
+				angles := c.Curve.Values
+				c.Curve.Values = unwrapDegrees(angles)
+				n := c.Curve.Simplify(tolerance)
+				if n == 0 {
+					c.Curve.Values = angles
+				}
+				removed += n
+			}
+		}
+	}
+	return removed
+}
+
+func midpoint(a, b time.Duration) time.Duration {
+	return a + (b-a)/2
+}
+
+func within(a, b, tolerance float32) bool {
+	d := a - b
+	return d <= tolerance && d >= -tolerance
+}
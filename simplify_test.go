@@ -0,0 +1,89 @@
+package ofbx
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func frames(values ...float32) *AnimationCurve {
+	c := &AnimationCurve{Values: values}
+	for i := range values {
+		c.Times = append(c.Times, time.Duration(i)*time.Second/30)
+	}
+	return c
+}
+
+func TestAnimationCurveSimplify(t *testing.T) {
+	ramp := frames(0, 1, 2, 3, 4, 5, 6)
+	require.Equal(t, 5, ramp.Simplify(1e-4))
+	require.Equal(t, []float32{0, 6}, ramp.Values)
+	require.Equal(t, []time.Duration{0, 200 * time.Millisecond}, ramp.Times)
+
+	held := frames(0, 2, 2, 2, 2, 0)
+	require.Equal(t, 2, held.Simplify(0.001))
+	require.Equal(t, []float32{0, 2, 2, 0}, held.Values)
+
+	noisy := frames(0, 1.01, 1.99, 3, 4.02, 5)
+	require.Equal(t, 0, frames(0, 1.01, 1.99, 3, 4.02, 5).Simplify(0.001))
+	require.Equal(t, 4, noisy.Simplify(0.05))
+
+	sine := frames()
+	for i := 0; i <= 60; i++ {
+		sine.Times = append(sine.Times, time.Duration(i)*time.Second/30)
+		sine.Values = append(sine.Values, float32(math.Sin(float64(i)/10)))
+	}
+	orig := &AnimationCurve{Times: append([]time.Duration{}, sine.Times...), Values: append([]float32{}, sine.Values...)}
+	removed := sine.Simplify(0.01)
+	require.True(t, removed > 30)
+	for _, tm := range orig.Times {
+		require.InDelta(t, orig.Evaluate(tm), sine.Evaluate(tm), 0.01)
+	}
+
+	// Steps stay steps
+	step := frames(0, 0, 0, 5, 5, 5)
+	step.AttrFlags = []int64{keyInterpolationConstant}
+	step.AttrData = []float32{0, 0, 0, 0}
+	step.AttrRefCount = []int64{6}
+	step.decodeKeys()
+	require.Equal(t, 3, step.Simplify(0.001))
+	require.Equal(t, []float32{0, 5, 5}, step.Values)
+	require.Equal(t, 100*time.Millisecond, step.Times[1])
+	require.Equal(t, float32(0), step.Evaluate(90*time.Millisecond))
+	require.Equal(t, []int64{keyInterpolationConstant}, step.AttrFlags)
+	require.Equal(t, []int64{3}, step.AttrRefCount)
+}
+
+func TestAnimationCurveSimplifyCubic(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	curve := scene.AnimationStacks[0].Layers[0].CurveNodes[0].Curves[2].Curve
+	flags, data := curve.AttrFlags, curve.AttrData
+	require.Equal(t, 0, curve.Simplify(1))
+	require.Equal(t, flags, curve.AttrFlags)
+	require.Equal(t, data[:2], curve.AttrData[:2])
+
+	// Re-encoding keys reads back the same keys
+	keys := curve.Keys
+	curve.encodeKeys()
+	curve.decodeKeys()
+	require.Equal(t, keys, curve.Keys)
+	require.Equal(t, []int64{3}, curve.AttrRefCount)
+}
+
+func TestAnimationStackSimplify(t *testing.T) {
+	rotation := frames(170, 175, -180, -175, -170)
+	stack := &AnimationStack{Layers: []*AnimationLayer{{CurveNodes: []*AnimationCurveNode{{
+		BoneLinkProp: BoneRotate,
+		Curves:       []Curve{{Curve: rotation}},
+	}}}}}
+	require.Equal(t, 3, stack.Simplify(0.001))
+	require.Equal(t, []float32{170, 190}, rotation.Values)
+
+	// A curve nothing is removed from is left as it was
+	rotation = frames(170, -170, 170)
+	stack.Layers[0].CurveNodes[0].Curves[0].Curve = rotation
+	require.Equal(t, 0, stack.Simplify(0.001))
+	require.Equal(t, []float32{170, -170, 170}, rotation.Values)
+}
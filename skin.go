@@ -1,5 +1,7 @@
 package ofbx
 
+import "sort"
+
 // Skin is a mapping for textures that denotes the control points to act on
 type Skin struct {
 	Object
@@ -30,3 +32,82 @@ func (s *Skin) stringPrefix(prefix string) string {
 	}
 	return str
 }
+
+// Influences are the clusters acting on each vertex of a Skin's Geometry, strongest first
+type Influences struct {
+	// Clusters holds indices into Skin.Clusters for each vertex, and Weights their weights,
+	// which sum to one. Every vertex has the same number of entries, unused ones are
+	// cluster 0 with a weight of 0.
+	Clusters [][]int
+	Weights  [][]float64
+	// Unweighted lists the vertices no cluster acts on, which have only unused entries
+	Unweighted []int
+}
+
+// VertexInfluences inverts the Skin's clusters onto its Geometry's Vertices, keeping the
+// maxInfluences strongest on each vertex, or as many as any vertex has if maxInfluences
+// is not positive. Clusters without a Link and weights that aren't positive are ignored.
+func (s *Skin) VertexInfluences(maxInfluences int) *Influences {
+	type influence struct {
+		cluster int
+		weight  float64
+	}
+	count := 0
+	if geom, ok := resolveObjectLinkReverse(s, GEOMETRY).(*Geometry); ok {
+		count = len(geom.Vertices)
+	}
+	for _, c := range s.Clusters {
+		for _, v := range c.Indices {
+			if v >= count {
+				count = v + 1
+			}
+		}
+	}
+
+	perVertex := make([][]influence, count)
+	for ci, c := range s.Clusters {
+		if c.Link == nil {
+			continue
+		}
+		for i, v := range c.Indices {
+			if v >= 0 && i < len(c.Weights) && c.Weights[i] > 0 {
+				perVertex[v] = append(perVertex[v], influence{ci, c.Weights[i]})
+			}
+		}
+	}
+	if maxInfluences <= 0 {
+		for _, infs := range perVertex {
+			if len(infs) > maxInfluences {
+				maxInfluences = len(infs)
+			}
+		}
+	}
+
+	out := &Influences{
+		Clusters: make([][]int, count),
+		Weights:  make([][]float64, count),
+	}
+	for v, infs := range perVertex {
+		out.Clusters[v] = make([]int, maxInfluences)
+		out.Weights[v] = make([]float64, maxInfluences)
+		if len(infs) == 0 {
+			out.Unweighted = append(out.Unweighted, v)
+			continue
+		}
+		sort.SliceStable(infs, func(i, j int) bool {
+			return infs[i].weight > infs[j].weight
+		})
+		if len(infs) > maxInfluences {
+			infs = infs[:maxInfluences]
+		}
+		total := 0.0
+		for _, inf := range infs {
+			total += inf.weight
+		}
+		for i, inf := range infs {
+			out.Clusters[v][i] = inf.cluster
+			out.Weights[v][i] = inf.weight / total
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package ofbx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVertexInfluences(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	skin := scene.Meshes[0].Geometry.Skin
+
+	infs := skin.VertexInfluences(4)
+	require.Len(t, infs.Clusters, 8)
+	require.Equal(t, []int{0, 0, 0, 0}, infs.Clusters[0])
+	require.Equal(t, []float64{1, 0, 0, 0}, infs.Weights[0])
+	require.Equal(t, []int{0, 1, 0, 0}, infs.Clusters[3])
+	require.Equal(t, []float64{0.5, 0.5, 0, 0}, infs.Weights[3])
+	require.Equal(t, []int{1, 0, 0, 0}, infs.Clusters[7])
+	require.Empty(t, infs.Unweighted)
+
+	// Vertex 3 is the only one with two influences
+	infs = skin.VertexInfluences(0)
+	require.Len(t, infs.Weights[0], 2)
+
+	skin.Clusters[1].Weights[0] = 0.25
+	infs = skin.VertexInfluences(1)
+	require.Equal(t, []int{0}, infs.Clusters[3])
+	require.Equal(t, []float64{1}, infs.Weights[3])
+
+	skin.Clusters[0].Weights[0] = 0
+	infs = skin.VertexInfluences(2)
+	require.Equal(t, []int{0}, infs.Unweighted)
+	require.Equal(t, []float64{0, 0}, infs.Weights[0])
+}
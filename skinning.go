@@ -0,0 +1,121 @@
+package ofbx
+
+import (
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// SkinnedVertices deforms the Mesh's Geometry with linear blend skinning at time t of
+// the given stack, or in the scene's static pose if stack is nil. It returns a world
+// space position for each of the Geometry's Vertices, and a normal for each of its
+// Normals. Blend shapes are applied before skinning. Vertices no cluster acts on,
+// and meshes without skins, follow the Mesh's own transform.
+//
+// As in the FBX SDK, the skinning mode is the first cluster's LinkMode: Normalize
+// divides each vertex's weighted transforms by its total weight and TotalOne gives
+// any weight missing from one to the unskinned transform. Additive clusters are
+// applied on top of the others, each moved towards by its weight.
+func (m *Mesh) SkinnedVertices(stack *AnimationStack, t time.Duration) (positions, normals []floatgeom.Point3) {
+	geom := m.Geometry
+	if geom == nil {
+		return nil, nil
+	}
+	rigid := m.GlobalTransformAt(stack, t).Mul(m.GeometricTransform())
+	transforms := make([]Matrix, len(geom.Vertices))
+	for i := range transforms {
+		transforms[i] = rigid
+	}
+
+	if skin := geom.Skin; skin != nil && len(skin.Clusters) != 0 {
+		mode := skin.Clusters[0].LinkMode
+		sums := make([]Matrix, len(geom.Vertices))
+		weights := make([]float64, len(geom.Vertices))
+		var additive []*Cluster
+		for _, c := range skin.Clusters {
+			if c.Link == nil {
+				continue
+			}
+			if c.LinkMode == LinkAdditive {
+				additive = append(additive, c)
+				continue
+			}
+			deform := c.deformationAt(stack, t, m)
+			for j, v := range c.Indices {
+				if v < 0 || v >= len(sums) || j >= len(c.Weights) {
+					continue
+				}
+				sums[v] = sums[v].addScaled(deform, c.Weights[j])
+				weights[v] += c.Weights[j]
+			}
+		}
+		for v, w := range weights {
+			switch {
+			case w == 0:
+			case mode == LinkTotalOne:
+				transforms[v] = sums[v].addScaled(rigid, 1-w)
+			default:
+				transforms[v] = Matrix{}.addScaled(sums[v], 1/w)
+			}
+		}
+		for _, c := range additive {
+			// The deformation moves a vertex from its bind pose, so it applies in bind space
+			delta := c.Link.GlobalTransformAt(stack, t).Mul(c.inverseTransformLink())
+			for j, v := range c.Indices {
+				if v < 0 || v >= len(transforms) || j >= len(c.Weights) {
+					continue
+				}
+				step := IdentityMatrix().addScaled(IdentityMatrix(), -c.Weights[j]).addScaled(delta, c.Weights[j])
+				transforms[v] = step.Mul(transforms[v])
+			}
+		}
+	}
+
+	positions, normals = geom.MorphedVertices(stack, t)
+	for i, v := range positions {
+		positions[i] = transforms[i].TransformPoint(v)
+	}
+	if len(normals) != 0 {
+		normalTransforms := make(map[int]Matrix)
+		pv := 0
+		for _, face := range geom.Faces {
+			for _, ci := range face {
+				if pv >= len(normals) {
+					break
+				}
+				nm, ok := normalTransforms[ci]
+				if !ok {
+					nm = rigid
+					if ci >= 0 && ci < len(transforms) {
+						nm = transforms[ci]
+					}
+					inv, _ := nm.Inverse()
+					nm = inv.Transpose()
+					normalTransforms[ci] = nm
+				}
+				normals[pv] = nm.TransformDirection(normals[pv]).Normalize()
+				pv++
+			}
+		}
+	}
+	return positions, normals
+}
+
+// deformationAt returns the transform taking the mesh's vertices to where the
+// cluster's link has moved them in world space at time t
+func (c *Cluster) deformationAt(stack *AnimationStack, t time.Duration, m *Mesh) Matrix {
+	return c.Link.GlobalTransformAt(stack, t).Mul(c.inverseTransformLink()).Mul(c.Transform).Mul(m.GeometricTransform())
+}
+
+func (c *Cluster) inverseTransformLink() Matrix {
+	inv, _ := c.TransformLink.Inverse()
+	return inv
+}
+
+// addScaled returns m1 + m2 * scale
+func (m1 Matrix) addScaled(m2 Matrix, scale float64) Matrix {
+	for i := range m1.m {
+		m1.m[i] += m2.m[i] * scale
+	}
+	return m1
+}
@@ -0,0 +1,69 @@
+package ofbx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func requirePoint3InDelta(t *testing.T, expected, actual floatgeom.Point3, delta float64) {
+	t.Helper()
+	for i := 0; i < 3; i++ {
+		require.InDelta(t, expected[i], actual[i], delta, "component %d of %v", i, actual)
+	}
+}
+
+func TestSkinnedVertices(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	// Move the fixture's offset cluster Transforms onto the bind pose
+	scene.RestoreBindPose(1e-6)
+	mesh := scene.Meshes[0]
+	geom := mesh.Geometry
+	stack := scene.AnimationStacks[0]
+
+	// In the bind pose every vertex is where the geometry has it
+	positions, normals := mesh.SkinnedVertices(stack, 0)
+	require.Len(t, positions, len(geom.Vertices))
+	require.Len(t, normals, len(geom.Normals))
+	for i, v := range geom.Vertices {
+		requirePoint3InDelta(t, v, positions[i], 1e-9)
+	}
+	for i, n := range geom.Normals {
+		requirePoint3InDelta(t, n, normals[i], 1e-9)
+	}
+
+	cluster := geom.Skin.Clusters[1]
+	bone := cluster.Link.GlobalTransformAt(stack, time.Second)
+	inv, _ := cluster.TransformLink.Inverse()
+	moved := bone.Mul(inv)
+	positions, normals = mesh.SkinnedVertices(stack, time.Second)
+	// Vertex 4 only follows the bone, vertex 3 is split evenly with the root
+	requirePoint3InDelta(t, moved.TransformPoint(geom.Vertices[4]), positions[4], 1e-9)
+	half := moved.TransformPoint(geom.Vertices[3]).Add(geom.Vertices[3]).MulConst(0.5)
+	requirePoint3InDelta(t, half, positions[3], 1e-9)
+	requirePoint3InDelta(t, geom.Vertices[0], positions[0], 1e-9)
+	// The second face only uses bone vertices
+	requirePoint3InDelta(t, moved.TransformDirection(geom.Normals[4]), normals[4], 1e-9)
+
+	// Missing weight goes to the mesh's own transform in TotalOne mode
+	root := geom.Skin.Clusters[0]
+	root.Weights[0] = 0.5
+	positions, _ = mesh.SkinnedVertices(nil, 0)
+	requirePoint3InDelta(t, geom.Vertices[0], positions[0], 1e-9)
+	root.LinkMode = LinkTotalOne
+	positions, _ = mesh.SkinnedVertices(nil, 0)
+	offset := floatgeom.Point3{1.5, -2, 0.3}.MulConst(0.5)
+	requirePoint3InDelta(t, geom.Vertices[0].Add(offset), positions[0], 1e-9)
+
+	// Additive clusters move vertices on from where the others put them
+	cluster.LinkMode = LinkAdditive
+	positions, _ = mesh.SkinnedVertices(stack, time.Second)
+	requirePoint3InDelta(t, moved.Mul(mesh.GlobalTransform()).TransformPoint(geom.Vertices[4]), positions[4], 1e-9)
+
+	// Without a skin the mesh moves rigidly
+	geom.Skin = nil
+	positions, _ = mesh.SkinnedVertices(nil, 0)
+	requirePoint3InDelta(t, mesh.GlobalTransform().TransformPoint(geom.Vertices[6]), positions[6], 1e-9)
+}
@@ -1,26 +1,136 @@
 package ofbx
 
+import (
+	"fmt"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
 //TextureType determines how a texture be used
 type TextureType int
 
 // Texture type block
 const (
-	DIFFUSE      TextureType = iota
-	NORMAL       TextureType = iota
-	TextureCOUNT TextureType = iota
+	DIFFUSE             TextureType = iota
+	NORMAL              TextureType = iota
+	SPECULAR            TextureType = iota
+	SPECULAR_FACTOR     TextureType = iota
+	SHININESS           TextureType = iota
+	EMISSIVE            TextureType = iota
+	AMBIENT             TextureType = iota
+	BUMP                TextureType = iota
+	TRANSPARENCY        TextureType = iota
+	REFLECTION          TextureType = iota
+	DISPLACEMENT        TextureType = iota
+	VECTOR_DISPLACEMENT TextureType = iota
+	TextureCOUNT        TextureType = iota
+)
+
+// textureProperties maps the material properties textures connect to onto their slots
+var textureProperties = map[string]TextureType{
+	"DiffuseColor":            DIFFUSE,
+	"NormalMap":               NORMAL,
+	"SpecularColor":           SPECULAR,
+	"SpecularFactor":          SPECULAR_FACTOR,
+	"ShininessExponent":       SHININESS,
+	"Shininess":               SHININESS,
+	"EmissiveColor":           EMISSIVE,
+	"AmbientColor":            AMBIENT,
+	"Bump":                    BUMP,
+	"TransparentColor":        TRANSPARENCY,
+	"TransparencyFactor":      TRANSPARENCY,
+	"ReflectionColor":         REFLECTION,
+	"DisplacementColor":       DISPLACEMENT,
+	"VectorDisplacementColor": VECTOR_DISPLACEMENT,
+}
+
+var textureTypeStrings = map[TextureType]string{
+	DIFFUSE:             "diffuse",
+	NORMAL:              "normal",
+	SPECULAR:            "specular",
+	SPECULAR_FACTOR:     "specular factor",
+	SHININESS:           "shininess",
+	EMISSIVE:            "emissive",
+	AMBIENT:             "ambient",
+	BUMP:                "bump",
+	TRANSPARENCY:        "transparency",
+	REFLECTION:          "reflection",
+	DISPLACEMENT:        "displacement",
+	VECTOR_DISPLACEMENT: "vector displacement",
+}
+
+func (tt TextureType) String() string {
+	return textureTypeStrings[tt]
+}
+
+// WrapMode is how a texture is sampled outside of the 0 to 1 UV range
+type WrapMode int
+
+// Wrap mode values
+const (
+	WrapRepeat WrapMode = iota
+	WrapClamp  WrapMode = iota
+)
+
+// AlphaSource is where a texture's alpha comes from
+type AlphaSource int
+
+// Alpha source values
+const (
+	AlphaNone         AlphaSource = iota
+	AlphaRGBIntensity AlphaSource = iota
+	AlphaBlack        AlphaSource = iota
+)
+
+// TextureBlendMode is how a texture is blended onto those under it in a layered texture.
+// Files may hold blend modes past TextureBlendNormal, which have no constant.
+type TextureBlendMode int
+
+// Texture blend mode values
+const (
+	TextureBlendTranslucent TextureBlendMode = iota
+	TextureBlendAdditive    TextureBlendMode = iota
+	TextureBlendModulate    TextureBlendMode = iota
+	TextureBlendModulate2   TextureBlendMode = iota
+	TextureBlendOver        TextureBlendMode = iota
+	TextureBlendNormal      TextureBlendMode = iota
 )
 
 //Texture is a texture file on an object
 type Texture struct {
 	Object
-	filename         *DataView
-	relativeFilename *DataView
+	// Filename is the absolute path the texture was saved with, and RelativeFilename
+	// its path relative to the FBX file
+	Filename         string
+	RelativeFilename string
+	// UVSet names the UV set of a Geometry the texture is mapped with
+	UVSet string
+	// Translation, Rotation and Scaling transform the UVs the texture is sampled at.
+	// Rotation is in degrees around Z.
+	Translation floatgeom.Point3
+	Rotation    floatgeom.Point3
+	Scaling     floatgeom.Point3
+	WrapModeU   WrapMode
+	WrapModeV   WrapMode
+	UseMipMap   bool
+	AlphaSource AlphaSource
+	// Alpha is the texture's opacity, from 0 to 1
+	Alpha       float64
+	Premultiply bool
+	BlendMode   TextureBlendMode
+	// Cropping is how many pixels are cropped from the image's left, top, right and bottom
+	Cropping [4]int
 }
 
 // NewTexture creates a texture
 func NewTexture(scene *Scene, element *Element) *Texture {
 	t := &Texture{
-		Object: *NewObject(scene, element),
+		Object:      *NewObject(scene, element),
+		UVSet:       "default",
+		Scaling:     floatgeom.Point3{1, 1, 1},
+		Alpha:       1,
+		Premultiply: true,
+		BlendMode:   TextureBlendAdditive,
 	}
 	return t
 }
@@ -30,17 +140,39 @@ func (t *Texture) Type() Type {
 	return TEXTURE
 }
 
-func (t *Texture) getFileName() *DataView {
-	return t.filename
+// UVChannel returns the index of the UVs of g the texture is mapped with, the one
+// named by its UVSet. Textures with a UVSet g doesn't have use its first UVs.
+func (t *Texture) UVChannel(g *Geometry) int {
+	for i, name := range g.UVNames {
+		if name != "" && name == t.UVSet && len(g.UVs[i]) != 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// UVTransform returns the transform from a Geometry's UVs to the coordinates the texture
+// is sampled at: its Scaling, then Rotation, then Translation
+func (t *Texture) UVTransform() Matrix {
+	scale := IdentityMatrix()
+	scale.m[0] = t.Scaling.X()
+	scale.m[5] = t.Scaling.Y()
+	mtx := EulerXYZ.rotationMatrix(floatgeom.Point3{0, 0, t.Rotation.Z()})
+	setTranslation(floatgeom.Point3{t.Translation.X(), t.Translation.Y(), 0}, &mtx)
+	return mtx.Mul(scale)
 }
 
-func (t *Texture) getRelativeFileName() *DataView {
-	return t.relativeFilename
+// TransformUV returns where the texture is sampled for the Geometry UV uv
+func (t *Texture) TransformUV(uv floatgeom.Point2) floatgeom.Point2 {
+	p := t.UVTransform().TransformPoint(floatgeom.Point3{uv.X(), uv.Y(), 0})
+	return floatgeom.Point2{p.X(), p.Y()}
 }
 
 func (t *Texture) String() string {
 	s := "Texture: " + t.Object.String()
-	s += ", filename: " + t.filename.String()
-	s += ", relativeFilename: " + t.relativeFilename.String()
+	s += ", filename: " + t.Filename
+	s += ", relativeFilename: " + t.RelativeFilename
+	s += ", uvSet: " + t.UVSet
+	s += fmt.Sprintf(", wrap: %d,%d", t.WrapModeU, t.WrapModeV)
 	return s
 }
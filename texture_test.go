@@ -0,0 +1,107 @@
+package ofbx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+const uvSetScene = `; FBX 7.4.0 project file
+Objects:  {
+	Geometry: 200, "Geometry::Quad", "Mesh" {
+		Vertices: *9 {
+			a: 0,0,0,1,0,0,1,1,0
+		} 
+		PolygonVertexIndex: *3 {
+			a: 0,1,-3
+		} 
+		LayerElementUV: 0 {
+			Name: "map1"
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			UV: *6 {
+				a: 0,0,1,0,1,1
+			} 
+		}
+		LayerElementUV: 1 {
+			Name: "detail"
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			UV: *6 {
+				a: 0,0,0.5,0,0.5,0.5
+			} 
+		}
+	}
+	Model: 100, "Model::Quad", "Mesh" {
+	}
+	Material: 600, "Material::Tiled", "" {
+	}
+	Texture: 700, "Texture::Detail", "" {
+		Type: "TextureVideoClip"
+		Version: 202
+		TextureName: "Texture::Detail"
+		Properties70:  {
+			P: "UVSet", "KString", "", "", "detail"
+			P: "Translation", "Vector", "", "A",0.5,0.25,0
+			P: "Rotation", "Vector", "", "A",0,0,90
+			P: "Scaling", "Vector", "", "A",2,2,1
+			P: "WrapModeU", "enum", "", "",1
+			P: "UseMipMap", "bool", "", "",1
+			P: "AlphaSource", "enum", "", "",2
+			P: "PremultiplyAlpha", "bool", "", "",0
+			P: "CurrentTextureBlendMode", "enum", "", "",4
+		}
+		FileName: "C:/textures/detail.png"
+		RelativeFilename: "textures/detail.png"
+		Cropping: 1,2,3,4
+	}
+	Texture: 701, "Texture::Legacy", "" {
+		ModelUVTranslation: 0.1,0.2
+		ModelUVScaling: 3,4
+	}
+}
+Connections:  {
+	C: "OO",100,0
+	C: "OO",200,100
+	C: "OO",600,100
+	C: "OP",700,600, "DiffuseColor"
+	C: "OP",701,600, "NormalMap"
+}
+`
+
+func TestTextureModel(t *testing.T) {
+	scene, err := Load(strings.NewReader(uvSetScene))
+	require.Nil(t, err)
+	geom := scene.Meshes[0].Geometry
+	require.Equal(t, [MaxUvs]string{"map1", "detail"}, geom.UVNames)
+
+	mat := scene.Meshes[0].Materials[0]
+	tex := mat.Textures[DIFFUSE]
+	require.Equal(t, "C:/textures/detail.png", tex.Filename)
+	require.Equal(t, "textures/detail.png", tex.RelativeFilename)
+	require.Equal(t, "detail", tex.UVSet)
+	require.Equal(t, 1, tex.UVChannel(geom))
+	require.Equal(t, WrapClamp, tex.WrapModeU)
+	require.Equal(t, WrapRepeat, tex.WrapModeV)
+	require.True(t, tex.UseMipMap)
+	require.Equal(t, AlphaBlack, tex.AlphaSource)
+	require.False(t, tex.Premultiply)
+	require.Equal(t, TextureBlendOver, tex.BlendMode)
+	require.Equal(t, 1.0, tex.Alpha)
+	require.Equal(t, [4]int{1, 2, 3, 4}, tex.Cropping)
+	require.Equal(t, floatgeom.Point3{2, 2, 1}, tex.Scaling)
+
+	// Scaled to (2, 0), rotated to (0, 2) and offset
+	uv := tex.TransformUV(floatgeom.Point2{1, 0})
+	require.InDelta(t, 0.5, uv.X(), 1e-9)
+	require.InDelta(t, 2.25, uv.Y(), 1e-9)
+
+	legacy := mat.Textures[NORMAL]
+	require.Equal(t, "", legacy.Filename)
+	require.Equal(t, "default", legacy.UVSet)
+	require.Equal(t, 0, legacy.UVChannel(geom))
+	require.True(t, legacy.Premultiply)
+	require.Equal(t, floatgeom.Point2{3.1, 4.2}, legacy.TransformUV(floatgeom.Point2{1, 1}))
+}
@@ -2,6 +2,7 @@ package ofbx
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/binary"
 	"io"
 	"strconv"
@@ -148,6 +149,7 @@ func (c *Cursor) readProperty() (*Property, error) {
 
 func (c *Cursor) readElement(version uint16) (*Element, error) {
 	v, _ := c.Peek(12)
+	offset := c.ReadSoFar()
 	footer := true
 	for _, b := range v {
 		if b != 0 {
@@ -183,7 +185,7 @@ func (c *Cursor) readElement(version uint16) (*Element, error) {
 	}
 	//fmt.Println("Read short string", id)
 
-	element := Element{}
+	element := Element{Offset: offset}
 	element.ID = NewDataView(id)
 
 	element.Properties = make([]*Property, propCt)
@@ -219,16 +221,24 @@ func (c *Cursor) readElement(version uint16) (*Element, error) {
 	return &element, nil
 }
 
+// ReadElements reads the raw element tree of a binary or ASCII FBX file without
+// building a Scene from it. The returned root element has no ID; the file's
+// top level elements are its children.
+func ReadElements(r io.Reader) (*Element, error) {
+	return tokenize(r)
+}
+
 func tokenize(r io.Reader) (*Element, error) {
 	countReader := NewCountReader(r)
 	r2 := bufio.NewReader(countReader)
 	cursor := &Cursor{r2, countReader}
 	//fmt.Println("initial stats: ", r2.Buffered(), cursor.ReadSoFar())
 
-	ok := isBinary(cursor)
-	if !ok {
-		return nil, errors.New("Non-binary FBX")
+	magic, _ := cursor.Peek(len(binaryMagic))
+	if !isBinary(bytes.NewReader(magic)) {
+		return tokenizeText(cursor)
 	}
+	cursor.Discard(len(binaryMagic))
 
 	var header Header
 	err := binary.Read(cursor, binary.LittleEndian, &header)
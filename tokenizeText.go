@@ -2,7 +2,11 @@ package ofbx
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io"
+	"math"
+	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/pkg/errors"
@@ -37,6 +41,7 @@ func (c *Cursor) skipLine() {
 	c.skipInsignificantWhitespaces()
 }
 
+// skipWhitespaces skips all whitespace, including newlines, and any ; comments
 func (c *Cursor) skipWhitespaces() error {
 	for {
 		by, _, err := c.ReadRune()
@@ -46,14 +51,6 @@ func (c *Cursor) skipWhitespaces() error {
 		if unicode.IsSpace(by) {
 			continue
 		}
-		c.UnreadRune()
-		break
-	}
-	for {
-		by, _, err := c.ReadRune()
-		if err != nil {
-			return err
-		}
 		if by == ';' {
 			c.skipLine()
 			continue
@@ -68,11 +65,18 @@ func isTextTokenChar(c rune) bool {
 	return unicode.IsDigit(c) || unicode.IsLetter(c) || c == '_'
 }
 
+func isTextNumberChar(c rune) bool {
+	return unicode.IsDigit(c) || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
+
 func (c *Cursor) readTextToken() (*DataView, error) {
 	out := bytes.NewBuffer([]byte{})
 	for {
 		r, _, err := c.ReadRune()
 		if err != nil {
+			if err == io.EOF {
+				break
+			}
 			return nil, err
 		}
 		if isTextTokenChar(r) {
@@ -85,7 +89,34 @@ func (c *Cursor) readTextToken() (*DataView, error) {
 	return BufferDataView(out), nil
 }
 
-func (c *Cursor) readTextProperty() (*Property, error) {
+// readTextNumber reads a literal like 12, -3.5 or 10.5e-013
+func (c *Cursor) readTextNumber() (string, error) {
+	out := strings.Builder{}
+	for {
+		r, _, err := c.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		if !isTextNumberChar(r) {
+			c.UnreadRune()
+			break
+		}
+		out.WriteRune(r)
+	}
+	if out.Len() == 0 {
+		return "", errors.New("Expected number")
+	}
+	return out.String(), nil
+}
+
+func isTextFloat(s string) bool {
+	return strings.ContainsAny(s, ".eE")
+}
+
+func (c *Cursor) readTextProperty(name string) (*Property, error) {
 	//fmt.Println("Reading text property")
 	prop := &Property{}
 
@@ -95,13 +126,13 @@ func (c *Cursor) readTextProperty() (*Property, error) {
 	}
 	if r == '"' {
 		//fmt.Println("Quote start")
-		prop.Type = 'S'
+		prop.Type = STRING
 		val := bytes.NewBuffer([]byte{})
 		for {
 			r, _, err := c.ReadRune()
 			if err != nil {
 				if err == io.EOF {
-					break //?
+					return nil, errors.New("Unterminated string")
 				}
 				return nil, err
 			}
@@ -110,157 +141,287 @@ func (c *Cursor) readTextProperty() (*Property, error) {
 			}
 			val.WriteRune(r)
 		}
-		prop.value = BufferDataView(val)
+		prop.value = textDataView(strings.Replace(val.String(), "&quot;", "\"", -1))
 		//fmt.Println("Quote end", prop.value.String())
 		return prop, nil
 	}
 
-	if unicode.IsDigit(r) || r == '-' {
-		//fmt.Println("Digit start")
-		prop.Type = 'L'
-		if r != '-' {
-			c.UnreadRune()
+	if unicode.IsDigit(r) || r == '-' || r == '+' || r == '.' {
+		c.UnreadRune()
+		val, err := c.readTextNumber()
+		if err != nil {
+			return nil, err
 		}
-		val := bytes.NewBuffer([]byte{})
-		for {
-			r, _, err := c.ReadRune()
-			if err != nil {
-				if err == io.EOF {
-					break //?
-				}
-				return nil, err
-			}
-			if !unicode.IsDigit(r) {
-				break
-			}
-			val.WriteRune(r)
+		prop.Type = LONG
+		if typ, ok := textScalarType(name); ok {
+			prop.Type = typ
+		} else if isTextFloat(val) {
+			prop.Type = DOUBLE
 		}
+		prop.value = textDataView(val)
+		return prop, nil
+	}
 
-		r, _, err = c.ReadRune()
-
-		if err == nil && r == '.' {
-			prop.Type = 'D'
-			val.WriteRune(r)
-			for {
-				r, _, err := c.ReadRune()
-				if err != nil {
-					if err == io.EOF {
-						break //?
-					}
-					return nil, err
-				}
-				if !unicode.IsDigit(r) {
-					break
-				}
-				val.WriteRune(r)
-			}
-			r, _, err = c.ReadRune()
-			if err == nil && r == 'e' || r == 'E' {
-				// 10.5e-013
-				val.WriteRune(r)
-				r, _, err = c.ReadRune()
-				if r != '-' || !unicode.IsDigit(r) {
-					return nil, errors.New("malformed floating point with exponent")
-				}
-				val.WriteRune(r)
-				for {
-					r, _, err := c.ReadRune()
-					if err != nil {
-						if err == io.EOF {
-							//fmt.Println("EOF?")
-							break //?
-						}
-						return nil, err
-					}
-					if !unicode.IsDigit(r) {
-						break
-					}
-					val.WriteRune(r)
-				}
-			}
+	if unicode.IsLetter(r) {
+		// Single letters like Shading: T are stored as chars, same as the
+		// binary format. Anything longer is an unquoted string.
+		c.UnreadRune()
+		tok, err := c.readTextToken()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Len() == 1 {
+			prop.Type = BOOL
+			prop.value = tok
+			return prop, nil
 		}
-		prop.value = BufferDataView(val)
-		//fmt.Println("Digits end", prop.value.String())
+		prop.Type = STRING
+		prop.value = textDataView(tok.String())
 		return prop, nil
 	}
 
-	if r == 'T' || r == 'Y' {
-		// WTF is this
-		//fmt.Println("WTF start")
-		prop.Type = PropertyType(r)
-		b, err := c.ReadByte()
-		prop.value = NewDataView(string(b))
-		//fmt.Println("WTF end", b)
-		return prop, err
-	}
 	if r == '*' {
-		//fmt.Println("Asterisk start")
-		prop.Type = 'l'
-		// Vertices: *10740 { a: 14.2760353088379,... } //Pulled from original...
-		pBytes := bytes.NewBuffer([]byte{})
-		r2, _, _ := c.ReadRune()
-		pBytes.WriteRune(r2)
-		_, err := c.Peek(1)
-		for err == nil && r2 != ':' {
-			r2, _, _ = c.ReadRune()
-			pBytes.WriteRune(r2)
-			_, err = c.Peek(1)
-		}
-
-		c.skipInsignificantWhitespaces() //We assume it is insignificant, so we don't add to buffer
-
-		prop.Count = 0
-
-		isAny := false
-		_, err = c.Peek(1)
-		for err == nil && r2 != '}' {
-			if r2 == ',' {
-				if isAny {
-					prop.Count++
-				}
-				isAny = false
-			} else if !unicode.IsSpace(r2) && r2 != '\n' {
-				isAny = true
-			}
-			if r2 == '.' {
-				prop.Type = 'd'
-			}
-			r2, _, _ = c.ReadRune()
-			pBytes.WriteRune(r2)
-			_, err = c.Peek(1)
+		return c.readTextArray(name)
+	}
+	return nil, errors.New("Unexpected character in property: " + string(r))
+}
+
+// readTextArray reads an array property body, e.g.
+// Vertices: *6 { a: 14.2760353088379,0,-1,... }
+// and decodes it to the same little endian layout binary arrays use, so
+// array consumers don't need to care which format a file was in
+func (c *Cursor) readTextArray(name string) (*Property, error) {
+	if _, err := c.readTextNumber(); err != nil {
+		return nil, errors.Wrap(err, "Invalid array length")
+	}
+	c.skipWhitespaces()
+	if r, _, err := c.ReadRune(); err != nil || r != '{' {
+		return nil, errors.New("Expected { after array length")
+	}
+	c.skipWhitespaces()
+	if r, _, err := c.ReadRune(); err != nil || r != 'a' {
+		return nil, errors.New("Expected a: in array")
+	}
+	if r, _, err := c.ReadRune(); err != nil || r != ':' {
+		return nil, errors.New("Expected a: in array")
+	}
+
+	vals := []string{}
+	for {
+		if err := c.skipWhitespaces(); err != nil {
+			return nil, errors.Wrap(err, "Unterminated array")
+		}
+		by, err := c.Peek(1)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unterminated array")
+		}
+		if by[0] == '}' {
+			c.Discard(1)
+			break
+		}
+		if by[0] == ',' {
+			c.Discard(1)
+			continue
+		}
+		val, err := c.readTextNumber()
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return textArrayProperty(vals, textArrayTypes[name])
+}
+
+// textArrayProperty encodes vals as an array of typ. If typ is 0 the array
+// type is picked from the values.
+func textArrayProperty(vals []string, typ PropertyType) (*Property, error) {
+	prop := &Property{
+		Type:  typ,
+		Count: len(vals),
+	}
+	if typ == 0 {
+		var err error
+		if prop.Type, err = guessTextArrayType(vals); err != nil {
+			return nil, err
+		}
+	}
+
+	buff := bytes.NewBuffer(make([]byte, 0, len(vals)*prop.Type.Size()))
+	for _, v := range vals {
+		var err error
+		switch prop.Type {
+		case ArrayDOUBLE:
+			var f float64
+			f, err = strconv.ParseFloat(v, 64)
+			binary.Write(buff, binary.LittleEndian, f)
+		case ArrayFLOAT:
+			var f float64
+			f, err = strconv.ParseFloat(v, 32)
+			binary.Write(buff, binary.LittleEndian, float32(f))
+		case ArrayLONG:
+			var i int64
+			i, err = strconv.ParseInt(v, 10, 64)
+			binary.Write(buff, binary.LittleEndian, i)
+		case ArrayINT:
+			var i int64
+			i, err = strconv.ParseInt(v, 10, 32)
+			binary.Write(buff, binary.LittleEndian, int32(i))
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid array value")
+		}
+	}
+	prop.value = BufferDataView(buff)
+	return prop, nil
+}
+
+// guessTextArrayType picks the narrowest array type that can hold every value
+func guessTextArrayType(vals []string) (PropertyType, error) {
+	typ := ArrayINT
+	for _, v := range vals {
+		if isTextFloat(v) {
+			return ArrayDOUBLE, nil
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, "Invalid array value")
 		}
-		if isAny {
-			prop.Count++
+		if i > math.MaxInt32 || i < math.MinInt32 {
+			typ = ArrayLONG
+		}
+	}
+	return typ, nil
+}
+
+var (
+	// Array types of elements as binary files store them. Arrays not listed
+	// here are typed from their values.
+	textArrayTypes = map[string]PropertyType{
+		"Vertices":                ArrayDOUBLE,
+		"Normals":                 ArrayDOUBLE,
+		"NormalsW":                ArrayDOUBLE,
+		"Binormals":               ArrayDOUBLE,
+		"BinormalsW":              ArrayDOUBLE,
+		"Tangents":                ArrayDOUBLE,
+		"TangentsW":               ArrayDOUBLE,
+		"UV":                      ArrayDOUBLE,
+		"Colors":                  ArrayDOUBLE,
+		"Weights":                 ArrayDOUBLE,
+		"FullWeights":             ArrayDOUBLE,
+		"Transform":               ArrayDOUBLE,
+		"TransformLink":           ArrayDOUBLE,
+		"TransformAssociateModel": ArrayDOUBLE,
+		"Matrix":                  ArrayDOUBLE,
+		"KeyValueFloat":           ArrayFLOAT,
+		"KeyTime":                 ArrayLONG,
+		"PolygonVertexIndex":      ArrayINT,
+		"Edges":                   ArrayINT,
+		"Indexes":                 ArrayINT,
+		"UVIndex":                 ArrayINT,
+		"NormalsIndex":            ArrayINT,
+		"BinormalsIndex":          ArrayINT,
+		"TangentsIndex":           ArrayINT,
+		"ColorIndex":              ArrayINT,
+		"Materials":               ArrayINT,
+		"KeyAttrFlags":            ArrayINT,
+		"KeyAttrRefCount":         ArrayINT,
+	}
+	// Number types of elements as binary files store them
+	textScalarTypes = map[string]PropertyType{
+		"FBXHeaderVersion": INTEGER,
+		"FBXVersion":       INTEGER,
+		"EncryptionType":   INTEGER,
+		"Version":          INTEGER,
+		"GeometryVersion":  INTEGER,
+		"KeyVer":           INTEGER,
+		"Count":            INTEGER,
+		"NbPoseNodes":      INTEGER,
+		"MultiLayer":       INTEGER,
+		"MultiTake":        INTEGER,
+		"TypedIndex":       INTEGER,
+		"Layer":            INTEGER,
+		"ShowInfoOnMoving": INTEGER,
+		"ShowAudio":        INTEGER,
+		"Default":          DOUBLE,
+		"DeformPercent":    DOUBLE,
+		"Position":         DOUBLE,
+		"Up":               DOUBLE,
+		"LookAt":           DOUBLE,
+		"CameraOrthoZoom":  DOUBLE,
+		"AudioColor":       DOUBLE,
+	}
+	// Properties70 value types as binary files store them. Types not
+	// listed here are stored as doubles.
+	textPIntTypes = map[string]bool{
+		"bool":                   true,
+		"Bool":                   true,
+		"int":                    true,
+		"Integer":                true,
+		"enum":                   true,
+		"Enum":                   true,
+		"Visibility Inheritance": true,
+	}
+	textPLongTypes = map[string]bool{
+		"KTime":     true,
+		"ULongLong": true,
+		"LongLong":  true,
+	}
+)
+
+// textScalarType returns the type binary files store an element's numbers as,
+// if it is known. Layer elements, e.g. LayerElementNormal: 0, hold their index.
+func textScalarType(name string) (PropertyType, bool) {
+	if strings.HasPrefix(name, "LayerElement") {
+		return INTEGER, true
+	}
+	typ, ok := textScalarTypes[name]
+	return typ, ok
+}
+
+// typeTextP sets the types of a P: element's values from its declared type,
+// i.e. P: "Lcl Translation", "Lcl Translation", "", "A",1,2,3 holds doubles
+// even though its values have no decimal point
+func typeTextP(element *Element) {
+	if len(element.Properties) < 5 {
+		return
+	}
+	typeName := element.Properties[1].value.String()
+	typ := DOUBLE
+	if textPIntTypes[typeName] {
+		typ = INTEGER
+	} else if textPLongTypes[typeName] {
+		typ = LONG
+	}
+	for _, p := range element.Properties[4:] {
+		if p.Type == LONG || p.Type == DOUBLE {
+			p.Type = typ
 		}
-		prop.value = BufferDataView(pBytes)
-		//fmt.Println("Asterisk end", prop.value.String())
-		return prop, err
 	}
-	//fmt.Println("r was", string(r))
-	return nil, errors.New("TODO")
 }
 
 func (c *Cursor) readTextElement() (*Element, error) {
+	offset := c.ReadSoFar()
 	//fmt.Println("Read text token start")
 	id, err := c.readTextToken()
 	if err != nil {
 		return nil, err
 	}
+	if id.Len() == 0 {
+		by, _ := c.Peek(1)
+		return nil, errors.New("Expected element name, got " + string(by))
+	}
 	//fmt.Println("Read rune start")
 	r, _, err := c.ReadRune()
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Unexpected end of file")
 	}
 	//fmt.Println("Read rune complete")
 	if r != ':' {
-		return nil, errors.New("Unexpected end of file")
-	}
-	//fmt.Println("Skip whitespaces start")
-	if err = c.skipWhitespaces(); err != nil {
-		return nil, err
+		return nil, errors.New("Expected : after " + id.String())
 	}
+	c.skipInsignificantWhitespaces()
 
-	element := &Element{}
+	element := &Element{Offset: offset}
 	element.ID = id
 
 	//fmt.Println("Looping over properties")
@@ -269,99 +430,112 @@ func (c *Cursor) readTextElement() (*Element, error) {
 		by, err := c.Peek(1)
 		if err != nil {
 			if err == io.EOF {
-				break
+				return element, nil
 			}
 			return nil, err
 		}
-		if by[0] == '\n' || by[0] == '{' {
+		if by[0] == '\n' || by[0] == '{' || by[0] == '}' || by[0] == ';' {
 			break
 		}
-		prop, err := c.readTextProperty()
+		if by[0] == ',' {
+			// Content: , "..." starts with an empty property
+			c.Discard(1)
+			c.skipInsignificantWhitespaces()
+			continue
+		}
+		prop, err := c.readTextProperty(id.String())
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrap(err, "Reading property of "+id.String()+" failed")
 		}
+		element.Properties = append(element.Properties, prop)
+
+		c.skipInsignificantWhitespaces()
 		by, err = c.Peek(1)
-		if err != io.EOF {
-			if err != nil {
-				return nil, err
-			}
-			if by[0] == ',' {
-				c.Discard(1)
-				c.skipWhitespaces()
-			}
+		if err == nil && by[0] == ',' {
+			// Long property lists may continue onto the next line
+			c.Discard(1)
+			c.skipWhitespaces()
 		}
-		c.skipInsignificantWhitespaces()
-
-		element.Properties = append(element.Properties, prop)
 	}
 
-	r, _, err = c.ReadRune()
-	if err != nil {
-		return nil, err
+	if id.String() == "P" {
+		typeTextP(element)
 	}
-	if r == '{' {
-		c.skipWhitespaces()
-		for {
-			by, err := c.Peek(1)
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				return nil, err
-			}
-			if by[0] == '}' {
-				c.Discard(1)
-				break
-			}
-			child, err := c.readTextElement()
-			if err != nil {
-				return nil, err
-			}
-			c.skipWhitespaces()
 
-			element.Children = append(element.Children, child)
+	if by, err := c.Peek(1); err != nil || by[0] != '{' {
+		return element, nil
+	}
+	c.Discard(1)
+	for {
+		if err := c.skipWhitespaces(); err != nil {
+			return nil, errors.Wrap(err, "Unterminated element "+id.String())
+		}
+		by, err := c.Peek(1)
+		if err != nil {
+			return nil, errors.Wrap(err, "Unterminated element "+id.String())
+		}
+		if by[0] == '}' {
+			c.Discard(1)
+			break
+		}
+		child, err := c.readTextElement()
+		if err != nil {
+			return nil, err
+		}
+		element.Children = append(element.Children, child)
+	}
+	if id.String() == "AnimationCurve" {
+		if err := typeTextKeyAttrData(element); err != nil {
+			return nil, errors.Wrap(err, "Invalid KeyAttrDataFloat")
 		}
-	} else {
-		c.UnreadRune()
 	}
 	return element, nil
 }
 
-//Todo: make this matter
-func tokenizeText(r io.Reader) (*Element, error) {
-	return nil, nil
-	/*
-		cr := NewCountReader(r)
-		r2 := bufio.NewReader(cr)
-		cursor := Cursor{r2, cr}
-		root := &Element{}
-		element := &root.child
-		_, err := cursor.Peek(1)
-		fmt.Println("Looping tokenizeText")
-		for ; err != io.EOF; _, err = cursor.Peek(1) {
-			v, _, err := cursor.ReadRune()
-			if err != nil {
-				return nil, err
-			}
-			fmt.Println("Read rune from tokenizeText", v)
-			if v == ';' || v == '\r' || v == '\n' {
-				fmt.Println("Skipping line")
-				cursor.skipLine()
-			} else {
-				fmt.Println("Reading text element")
-				child, err := cursor.readTextElement()
-				fmt.Println("Read text element")
-				if err != nil {
-					fmt.Println("Read text element error", err)
-					return nil, err
-				}
-				*element = child
-				if element == nil {
-					return root, nil
-				}
-				element = &(*element).sibling
+// typeTextKeyAttrData stores a curve's KeyAttrDataFloat as floats, as binary
+// files do. Its packed pairs are written as integers in ASCII files, so which
+// values are packed depends on the curve's KeyAttrFlags.
+func typeTextKeyAttrData(curve *Element) error {
+	data := findSingleChildProperty(curve, "KeyAttrDataFloat")
+	if data == nil || data.Type == ArrayFLOAT {
+		return nil
+	}
+	var flags []int64
+	if attrFlags := findSingleChildProperty(curve, "KeyAttrFlags"); attrFlags != nil {
+		var err error
+		if flags, err = attrFlags.getValuesInt64(); err != nil {
+			return err
+		}
+	}
+	vals, err := parseKeyAttrData(data, flags)
+	if err != nil {
+		return err
+	}
+	buff := bytes.NewBuffer(make([]byte, 0, len(vals)*ArrayFLOAT.Size()))
+	binary.Write(buff, binary.LittleEndian, vals)
+	data.Type = ArrayFLOAT
+	data.Count = len(vals)
+	data.value = BufferDataView(buff)
+	return nil
+}
+
+func tokenizeText(cursor *Cursor) (*Element, error) {
+	root := &Element{}
+	// Skip a UTF-8 byte order mark, if any
+	if r, _, err := cursor.ReadRune(); err == nil && r != '\uFEFF' {
+		cursor.UnreadRune()
+	}
+	for {
+		if err := cursor.skipWhitespaces(); err != nil {
+			if err == io.EOF {
+				return root, nil
 			}
+			return nil, err
 		}
-		return root, nil
-	*/
+		child, err := cursor.readTextElement()
+		if err != nil {
+			return nil, err
+		}
+		root.Children = append(root.Children, child)
+	}
 }
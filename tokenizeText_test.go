@@ -0,0 +1,183 @@
+package ofbx
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+func textCursor(s string) *Cursor {
+	cr := NewCountReader(strings.NewReader(s))
+	return &Cursor{bufio.NewReader(cr), cr}
+}
+
+func TestReadTextProperty(t *testing.T) {
+	type testCase struct {
+		text  string
+		typ   PropertyType
+		value string
+	}
+	testCases := []testCase{
+		{`"Model::Cube"`, STRING, "Model::Cube"},
+		{`""`, STRING, ""},
+		{`12`, LONG, "12"},
+		{`-3`, LONG, "-3"},
+		{`46186158000`, LONG, "46186158000"},
+		{`-3.5`, DOUBLE, "-3.5"},
+		{`10.5e-013`, DOUBLE, "10.5e-013"},
+		{`1e+06`, DOUBLE, "1e+06"},
+		{`T`, BOOL, "T"},
+	}
+	for _, tc := range testCases {
+		p, err := textCursor(tc.text + "\n").readTextProperty("")
+		require.Nil(t, err, tc.text)
+		require.Equal(t, tc.typ, p.Type, tc.text)
+		require.Equal(t, tc.value, p.value.String(), tc.text)
+	}
+}
+
+func TestReadTextPropertyByName(t *testing.T) {
+	// Known elements are typed as binary files store them, whatever their literals look like
+	type testCase struct {
+		name string
+		text string
+		typ  PropertyType
+	}
+	testCases := []testCase{
+		{"FBXVersion", "7400", INTEGER},
+		{"Version", "100", INTEGER},
+		{"LayerElementNormal", "0", INTEGER},
+		{"Default", "0", DOUBLE},
+		{"Model", "100", LONG},
+		{"Vertices", "*3 {a: -1,-1,1}", ArrayDOUBLE},
+		{"KeyValueFloat", "*2 {a: 0,1}", ArrayFLOAT},
+		{"KeyTime", "*2 {a: 0,1}", ArrayLONG},
+		{"PolygonVertexIndex", "*3 {a: 0,1,-3}", ArrayINT},
+		{"Unknown", "*3 {a: 0,1.5,2}", ArrayDOUBLE},
+	}
+	for _, tc := range testCases {
+		p, err := textCursor(tc.text + "\n").readTextProperty(tc.name)
+		require.Nil(t, err, tc.name)
+		require.Equal(t, tc.typ, p.Type, tc.name)
+	}
+}
+
+func TestReadTextKeyAttrData(t *testing.T) {
+	root, err := tokenizeText(textCursor(`AnimationCurve: 503, "AnimCurve::", "" {
+	KeyAttrFlags: *1 {
+		a: 24840
+	}
+	KeyAttrDataFloat: *4 {
+		a: 0,0,255790911,0
+	}
+}`))
+	require.Nil(t, err)
+	data := findSingleChildProperty(root.Children[0], "KeyAttrDataFloat")
+	require.Equal(t, ArrayFLOAT, data.Type)
+	f32s, err := data.getValuesF32()
+	require.Nil(t, err)
+	require.Equal(t, uint32(255790911), math.Float32bits(f32s[2]))
+}
+
+func TestReadTextArray(t *testing.T) {
+	type testCase struct {
+		text  string
+		typ   PropertyType
+		count int
+	}
+	testCases := []testCase{
+		{"*3 {\n\ta: 1,-2,3\n}", ArrayINT, 3},
+		{"*2 { a: 0,46186158000 }", ArrayLONG, 2},
+		{"*3 {a: 1,2.5,3}", ArrayDOUBLE, 3},
+	}
+	for _, tc := range testCases {
+		p, err := textCursor(tc.text).readTextProperty("")
+		require.Nil(t, err, tc.text)
+		require.Equal(t, tc.typ, p.Type, tc.text)
+		require.Equal(t, tc.count, p.Count, tc.text)
+		f64s, err := parseArrayRawFloat64(p)
+		require.Nil(t, err, tc.text)
+		require.Len(t, f64s, p.Count)
+	}
+}
+
+func TestLoadText(t *testing.T) {
+	f, err := os.Open("testdata/cube.fbx")
+	require.Nil(t, err)
+	defer f.Close()
+	scene, err := Load(f)
+	require.Nil(t, err)
+
+	require.Equal(t, FrontVectorParityOdd, scene.Settings.FrontAxis)
+	require.Equal(t, float32(2.54), scene.Settings.UnitScaleFactor)
+	require.Equal(t, float32(24), scene.FrameRate)
+
+	require.Len(t, scene.Meshes, 1)
+	mesh := scene.Meshes[0]
+	require.Equal(t, "Model::Cube", mesh.Name())
+	require.Equal(t, floatgeom.Point3{1.5, -2, 0.3}, getLocalTranslation(mesh))
+
+	geom := mesh.Geometry
+	require.NotNil(t, geom)
+	require.Len(t, geom.Vertices, 8)
+	require.Equal(t, floatgeom.Point3{1, 1, 1}, geom.Vertices[6])
+	require.Len(t, geom.Faces, 6)
+	require.Equal(t, []int{0, 3, 2, 1}, geom.Faces[0])
+	// Attributes are stored per polygon vertex
+	require.Len(t, geom.Normals, 24)
+	require.Equal(t, floatgeom.Point3{0, 0, -1}, geom.Normals[0])
+	require.Len(t, geom.UVs[0], 24)
+	require.Equal(t, []int{0, 0, 0, 0, 0, 0}, geom.Materials)
+	require.Equal(t, floatgeom.Point2{1, 0}, geom.UVs[0][1])
+
+	require.Len(t, mesh.Materials, 1)
+	mat := mesh.Materials[0]
+	require.Equal(t, Color{0.8, 0.2, 0.2}, mat.DiffuseColor)
+	require.Equal(t, 25.5, mat.ShininessExponent)
+	require.NotNil(t, mat.Textures[DIFFUSE])
+	require.Equal(t, "textures/diffuse.png", mat.Textures[DIFFUSE].RelativeFilename)
+
+	require.NotNil(t, geom.Skin)
+	require.Len(t, geom.Skin.Clusters, 2)
+	cluster := geom.Skin.Clusters[1]
+	require.Equal(t, "Model::Bone", cluster.Link.Name())
+	require.Equal(t, []int{3, 4, 5, 6, 7}, cluster.Indices)
+	require.Equal(t, 3.0, cluster.TransformLink.m[13])
+
+	require.Len(t, scene.AnimationStacks, 1)
+	stack := scene.AnimationStacks[0]
+	require.Len(t, stack.Layers, 1)
+	require.Len(t, stack.Layers[0].CurveNodes, 1)
+	curveNode := stack.Layers[0].CurveNodes[0]
+	require.Equal(t, cluster.Link, curveNode.Bone)
+	require.Equal(t, BoneRotate, curveNode.BoneLinkProp)
+	curve := curveNode.Curves[2].Curve
+	require.Equal(t, []float32{0, 45, 90.5}, curve.Values)
+	require.Len(t, curve.Times, 3)
+	require.Equal(t, time.Second, curve.Times[2])
+
+	require.Len(t, scene.TakeInfos, 1)
+	require.Equal(t, "Take 001", scene.TakeInfos[0].name.String())
+	require.Equal(t, 1.0, scene.TakeInfos[0].localTimeTo)
+}
+
+func TestLoadTextWithoutTakes(t *testing.T) {
+	scene, err := Load(strings.NewReader(`; FBX 7.4.0 project file
+Objects:  {
+	Model: 100, "Model::Empty", "Null" {
+	}
+}
+Connections:  {
+	C: "OO",100,0
+}
+`))
+	require.Nil(t, err)
+	require.Empty(t, scene.TakeInfos)
+	require.Len(t, scene.RootNode.Children(), 1)
+}
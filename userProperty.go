@@ -0,0 +1,90 @@
+package ofbx
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+)
+
+// PropertyValue is a user defined property of an object, one written with the "U" flag
+type PropertyValue struct {
+	ObjectProperty
+	// EnumValues names the values of an enum property, in order
+	EnumValues []string
+	// CurveNodes are the curve nodes animating the property, from every animation layer
+	CurveNodes []*AnimationCurveNode
+	owner      Obj
+}
+
+// UserProperties returns the object's user defined properties by name. Members of
+// compound properties are named after their compound, as in "Compound|Member".
+func (o *Object) UserProperties() map[string]PropertyValue {
+	out := map[string]PropertyValue{}
+	for _, elem := range properties70(o.element) {
+		p, ok := newObjectProperty(elem, false)
+		if !ok || !strings.Contains(p.Flags, "U") {
+			continue
+		}
+		pv := PropertyValue{ObjectProperty: p, owner: o}
+		if p.Type == "Enum" || p.Type == "enum" {
+			// The enum's value names follow its value, separated by ~
+			if names := elem.getProperty(5); isString(names) && names.value.String() != "" {
+				pv.EnumValues = strings.Split(names.value.String(), "~")
+			}
+		}
+		out[p.Name] = pv
+	}
+	if len(out) == 0 || o.scene == nil {
+		return out
+	}
+	for _, stack := range o.scene.AnimationStacks {
+		for _, layer := range stack.Layers {
+			for _, cn := range layer.CurveNodes {
+				if cn.Bone == nil || cn.Bone.ID() != o.id {
+					continue
+				}
+				if pv, ok := out[cn.BoneLinkProp]; ok {
+					pv.CurveNodes = append(pv.CurveNodes, cn)
+					out[cn.BoneLinkProp] = pv
+				}
+			}
+		}
+	}
+	return out
+}
+
+// EnumName returns the name of an enum property's value, or "" if it has none
+func (pv PropertyValue) EnumName() string {
+	i, ok := pv.Value.(int64)
+	if !ok || i < 0 || int(i) >= len(pv.EnumValues) {
+		return ""
+	}
+	return pv.EnumValues[i]
+}
+
+// ValueAt returns the property's value at time t of the given stack, or its static
+// value if stack is nil, of the same type as Value. Only numeric, vector and color
+// properties are animated.
+func (pv PropertyValue) ValueAt(stack *AnimationStack, t time.Duration) interface{} {
+	if stack == nil || pv.owner == nil {
+		return pv.Value
+	}
+	switch v := pv.Value.(type) {
+	case float64:
+		return stack.evaluateProperty(pv.owner, pv.Name, v, t)
+	case int64:
+		return int64(math.Round(stack.evaluateProperty(pv.owner, pv.Name, float64(v), t)))
+	case bool:
+		f := 0.0
+		if v {
+			f = 1
+		}
+		return stack.evaluateProperty(pv.owner, pv.Name, f, t) >= 0.5
+	case floatgeom.Point3:
+		c := stack.evaluateChannels(pv.owner, pv.Name, []float64{v.X(), v.Y(), v.Z()}, t)
+		return floatgeom.Point3{c[0], c[1], c[2]}
+	}
+	return pv.Value
+}
@@ -0,0 +1,107 @@
+package ofbx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oakmound/oak/v2/alg/floatgeom"
+	"github.com/stretchr/testify/require"
+)
+
+const userPropertyScene = `; FBX 7.4.0 project file
+Objects:  {
+	Model: 100, "Model::Crate", "Mesh" {
+		Properties70:  {
+			P: "Lcl Translation", "Lcl Translation", "", "A",1,0,0
+			P: "CollisionType", "Enum", "", "A+U",1, "Box~Sphere~Mesh"
+			P: "Spawn", "Compound", "", ""
+			P: "Spawn|ID", "KString", "", "U", "spawn_07"
+			P: "Spawn|Enabled", "Bool", "", "A+U",1
+			P: "Health", "Number", "", "A+U",10
+			P: "Tint", "Color", "", "A+U",1,0,0
+		}
+	}
+	Material: 600, "Material::Glow", "" {
+		Properties70:  {
+			P: "Pulse", "Number", "", "A+U",0
+		}
+	}
+	AnimationStack: 500, "AnimStack::Take 001", "" {
+	}
+	AnimationLayer: 501, "AnimLayer::BaseLayer", "" {
+	}
+	AnimationCurveNode: 502, "AnimCurveNode::Health", "" {
+		Properties70:  {
+			P: "d|Health", "Number", "", "A",10
+		}
+	}
+	AnimationCurve: 503, "AnimCurve::", "" {
+		Default: 0
+		KeyVer: 4009
+		KeyTime: *2 {
+			a: 0,46186158000
+		} 
+		KeyValueFloat: *2 {
+			a: 10,0
+		} 
+	}
+	AnimationCurveNode: 504, "AnimCurveNode::Pulse", "" {
+		Properties70:  {
+			P: "d|Pulse", "Number", "", "A",0
+		}
+	}
+	AnimationCurve: 505, "AnimCurve::", "" {
+		Default: 0
+		KeyVer: 4009
+		KeyTime: *2 {
+			a: 0,46186158000
+		} 
+		KeyValueFloat: *2 {
+			a: 0,4
+		} 
+	}
+}
+Connections:  {
+	C: "OO",100,0
+	C: "OO",600,100
+	C: "OO",501,500
+	C: "OO",502,501
+	C: "OO",504,501
+	C: "OP",502,100, "Health"
+	C: "OP",503,502, "d|Health"
+	C: "OP",504,600, "Pulse"
+	C: "OP",505,504, "d|Pulse"
+}
+`
+
+func TestUserProperties(t *testing.T) {
+	scene, err := Load(strings.NewReader(userPropertyScene))
+	require.Nil(t, err)
+	crate := scene.Meshes[0]
+	props := crate.UserProperties()
+	require.Len(t, props, 5)
+	_, ok := props["Lcl Translation"]
+	require.False(t, ok)
+
+	collision := props["CollisionType"]
+	require.Equal(t, PropertyInt, collision.Kind)
+	require.Equal(t, []string{"Box", "Sphere", "Mesh"}, collision.EnumValues)
+	require.Equal(t, "Sphere", collision.EnumName())
+	require.Equal(t, "spawn_07", props["Spawn|ID"].Value)
+	require.Equal(t, true, props["Spawn|Enabled"].Value)
+	require.Equal(t, floatgeom.Point3{1, 0, 0}, props["Tint"].Value)
+
+	stack := scene.AnimationStacks[0]
+	health := props["Health"]
+	require.Len(t, health.CurveNodes, 1)
+	require.Equal(t, 10.0, health.ValueAt(nil, 0))
+	require.Equal(t, 5.0, health.ValueAt(stack, 500*time.Millisecond))
+	require.Empty(t, props["Tint"].CurveNodes)
+	require.Equal(t, floatgeom.Point3{1, 0, 0}, props["Tint"].ValueAt(stack, time.Second))
+
+	// Objects other than nodes are animated through property connections too
+	pulse := crate.Materials[0].UserProperties()["Pulse"]
+	require.Len(t, pulse.CurveNodes, 1)
+	require.Equal(t, 2.0, pulse.ValueAt(stack, 500*time.Millisecond))
+}
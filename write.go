@@ -0,0 +1,286 @@
+package ofbx
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ArrayCompression dictates whether array properties are zlib compressed when written
+type ArrayCompression int
+
+// ArrayCompression options
+const (
+	// KeepCompression writes each array with the Encoding it already has
+	KeepCompression ArrayCompression = iota
+	// NoCompression writes every array uncompressed
+	NoCompression ArrayCompression = iota
+	// CompressArrays zlib compresses every non-empty array
+	CompressArrays ArrayCompression = iota
+)
+
+// DefaultWriteVersion is the FBX version written when WriteOptions.Version is unset
+const DefaultWriteVersion = 7400
+
+// WriteOptions control how a binary FBX file is written
+type WriteOptions struct {
+	// Version is written to the file header. Versions 7500 and above
+	// use 64 bit element offsets.
+	Version     uint32
+	Compression ArrayCompression
+}
+
+var (
+	// footerID and footerMagic are constant in every FBX file we have seen
+	footerID    = []byte{0xfa, 0xbc, 0xab, 0x09, 0xd0, 0xc8, 0xd4, 0x66, 0xb1, 0x76, 0xfb, 0x83, 0x1c, 0xf7, 0x26, 0x7e}
+	footerMagic = []byte{0xf8, 0x5a, 0x8c, 0x6a, 0xde, 0xf5, 0xd9, 0x7e, 0xec, 0xe9, 0x0c, 0xe3, 0x75, 0x8f, 0x29, 0x0b}
+)
+
+// Save writes the scene's element tree as a binary FBX file. Changes should be
+// made to scene.RootElement; parsed objects like Meshes are not written back.
+func Save(w io.Writer, scene *Scene, opts WriteOptions) error {
+	if scene == nil || scene.RootElement == nil {
+		return errors.New("Scene has no elements to write")
+	}
+	return WriteElement(w, scene.RootElement, opts)
+}
+
+// WriteElement writes an element tree as a binary FBX file. A root element with no ID,
+// like those built by Load, has its children written as the top level elements.
+func WriteElement(w io.Writer, root *Element, opts WriteOptions) error {
+	if opts.Version == 0 {
+		opts.Version = DefaultWriteVersion
+	}
+	bw := &binaryWriter{WriteOptions: opts}
+
+	bw.buf.Write(binaryMagic)
+	bw.buf.Write([]byte{0x1a, 0x00})
+	binary.Write(&bw.buf, binary.LittleEndian, opts.Version)
+
+	elems := []*Element{root}
+	if root.ID == nil {
+		elems = root.Children
+	}
+	for _, e := range elems {
+		if err := bw.writeElement(e, false); err != nil {
+			return err
+		}
+	}
+	bw.buf.Write(make([]byte, bw.sentinelLength()))
+	bw.writeFooter()
+
+	_, err := bw.buf.WriteTo(w)
+	return err
+}
+
+type binaryWriter struct {
+	WriteOptions
+	// Element offsets are absolute, so the file is built in memory
+	// and offsets are patched once each element's length is known
+	buf bytes.Buffer
+}
+
+func (bw *binaryWriter) offsetSize() int {
+	if bw.Version >= 7500 {
+		return 8
+	}
+	return 4
+}
+
+func (bw *binaryWriter) sentinelLength() int {
+	return 3*bw.offsetSize() + 1
+}
+
+func (bw *binaryWriter) writeOffset(v uint64) {
+	if bw.offsetSize() == 8 {
+		binary.Write(&bw.buf, binary.LittleEndian, v)
+		return
+	}
+	binary.Write(&bw.buf, binary.LittleEndian, uint32(v))
+}
+
+func (bw *binaryWriter) patchOffset(at int, v uint64) error {
+	data := bw.buf.Bytes()[at:]
+	if bw.offsetSize() == 8 {
+		binary.LittleEndian.PutUint64(data, v)
+		return nil
+	}
+	if v > math.MaxUint32 {
+		return errors.New("File too large for 32 bit offsets, use Version 7500 or greater")
+	}
+	binary.LittleEndian.PutUint32(data, uint32(v))
+	return nil
+}
+
+// writeElement writes e and its children. objectDef is set for the children of
+// Objects, whose second property is the object's name.
+func (bw *binaryWriter) writeElement(e *Element, objectDef bool) error {
+	id := ""
+	if e.ID != nil {
+		id = e.ID.String()
+	}
+	if len(id) > math.MaxUint8 {
+		return errors.New("Element id too long: " + id)
+	}
+
+	start := bw.buf.Len()
+	bw.writeOffset(0) // end offset
+	bw.writeOffset(uint64(len(e.Properties)))
+	bw.writeOffset(0) // property list length
+	bw.buf.WriteByte(byte(len(id)))
+	bw.buf.WriteString(id)
+
+	propStart := bw.buf.Len()
+	for i, p := range e.Properties {
+		if err := bw.writeProperty(p, objectDef && i == 1); err != nil {
+			return errors.Wrap(err, "Writing property of "+id+" failed")
+		}
+	}
+	propLength := bw.buf.Len() - propStart
+
+	// Elements with children, or with nothing at all, end in a null record
+	if len(e.Children) != 0 || len(e.Properties) == 0 {
+		for _, c := range e.Children {
+			if err := bw.writeElement(c, id == "Objects"); err != nil {
+				return err
+			}
+		}
+		bw.buf.Write(make([]byte, bw.sentinelLength()))
+	}
+
+	if err := bw.patchOffset(start, uint64(bw.buf.Len())); err != nil {
+		return err
+	}
+	return bw.patchOffset(start+2*bw.offsetSize(), uint64(propLength))
+}
+
+func (bw *binaryWriter) writeProperty(p *Property, objectName bool) error {
+	bw.buf.WriteByte(byte(p.Type))
+	if p.Type.IsArray() {
+		return bw.writeArray(p)
+	}
+	switch p.Type {
+	case STRING:
+		s := p.value.String()
+		if p.value.isText && objectName {
+			s = binaryObjectName(s)
+		}
+		binary.Write(&bw.buf, binary.LittleEndian, uint32(len(s)))
+		bw.buf.WriteString(s)
+		return nil
+	case RAWSTRING:
+		// Raw strings are stored with their length prefix
+		bw.buf.Write(p.value.bytes())
+		return nil
+	}
+	if !p.value.isText {
+		data := p.value.bytes()
+		if len(data) != p.Type.Size() {
+			return errors.New("Invalid length for property type " + string(p.Type))
+		}
+		bw.buf.Write(data)
+		return nil
+	}
+	var v interface{}
+	switch p.Type {
+	case BOOL:
+		v = p.value.toBool()
+	case INT16:
+		v = int16(p.value.toInt32())
+	case INTEGER:
+		v = p.value.toInt32()
+	case LONG:
+		v = p.value.toint64()
+	case FLOAT:
+		v = p.value.toFloat()
+	case DOUBLE:
+		v = p.value.toDouble()
+	default:
+		return errors.New("Did not know this property:" + string(p.Type))
+	}
+	return binary.Write(&bw.buf, binary.LittleEndian, v)
+}
+
+// arrayBytes returns the uncompressed little endian data of an array property
+func arrayBytes(p *Property) ([]byte, error) {
+	data := p.value.bytes()
+	switch p.Encoding {
+	case 0:
+		return data, nil
+	case 1:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.Wrap(err, "New Reader failed")
+		}
+		defer zr.Close()
+		data, err = ioutil.ReadAll(zr)
+		if err != nil {
+			return nil, errors.Wrap(err, "Decompressing array failed")
+		}
+		return data, nil
+	}
+	return nil, errors.New("Invalid encoding")
+}
+
+func (bw *binaryWriter) writeArray(p *Property) error {
+	data := p.value.bytes()
+	encoding := p.Encoding
+	if encoding > 1 {
+		return errors.New("Invalid encoding")
+	}
+	compress := encoding == 1
+	if bw.Compression == NoCompression {
+		compress = false
+	} else if bw.Compression == CompressArrays {
+		compress = p.Count != 0
+	}
+	if encoding == 1 && !compress {
+		var err error
+		if data, err = arrayBytes(p); err != nil {
+			return err
+		}
+		encoding = 0
+	} else if encoding == 0 && compress {
+		var zbuf bytes.Buffer
+		zw := zlib.NewWriter(&zbuf)
+		zw.Write(data)
+		if err := zw.Close(); err != nil {
+			return errors.Wrap(err, "Compressing array failed")
+		}
+		data = zbuf.Bytes()
+		encoding = 1
+	}
+
+	binary.Write(&bw.buf, binary.LittleEndian, uint32(p.Count))
+	binary.Write(&bw.buf, binary.LittleEndian, encoding)
+	binary.Write(&bw.buf, binary.LittleEndian, uint32(len(data)))
+	bw.buf.Write(data)
+	return nil
+}
+
+func (bw *binaryWriter) writeFooter() {
+	bw.buf.Write(footerID)
+	bw.buf.Write(make([]byte, 4))
+	// The version is aligned to 16 bytes, with at least one byte of padding
+	pad := 16 - bw.buf.Len()%16
+	bw.buf.Write(make([]byte, pad))
+	binary.Write(&bw.buf, binary.LittleEndian, bw.Version)
+	bw.buf.Write(make([]byte, 120))
+	bw.buf.Write(footerMagic)
+}
+
+// binaryObjectName converts an ASCII style object name, Model::Cube,
+// into the binary style, Cube\x00\x01Model
+func binaryObjectName(s string) string {
+	idx := strings.Index(s, "::")
+	if idx == -1 {
+		return s
+	}
+	return s[idx+2:] + "\x00\x01" + s[:idx]
+}
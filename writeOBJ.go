@@ -0,0 +1,222 @@
+package ofbx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMTLFileName is the material library WriteOBJ references when OBJOptions.MTLFileName is unset
+const DefaultMTLFileName = "scene.mtl"
+
+// OBJOptions control how WriteOBJ writes a scene
+type OBJOptions struct {
+	// MTLFileName is the name the OBJ references its material library by,
+	// i.e. where the data written to mtlW should be saved
+	MTLFileName string
+	// LocalSpace writes each mesh's vertices untransformed instead of in world space
+	LocalSpace bool
+}
+
+// WriteOBJ writes the scene's meshes as a Wavefront OBJ file to w, and their materials
+// as an MTL file to mtlW. Polygons are written as they are, without triangulating them.
+func WriteOBJ(w, mtlW io.Writer, scene *Scene, opts OBJOptions) error {
+	if scene == nil {
+		return errors.New("No scene to write")
+	}
+	if opts.MTLFileName == "" {
+		opts.MTLFileName = DefaultMTLFileName
+	}
+	ow := &objWriter{
+		textWriter: textWriter{Writer: bufio.NewWriter(w)},
+		materials:  make(map[uint64]string),
+		names:      make(map[string]bool),
+	}
+	ow.printf("# Written by ofbx\n")
+	ow.printf("mtllib %s\n", opts.MTLFileName)
+	for _, mesh := range scene.Meshes {
+		if err := ow.writeMesh(mesh, opts); err != nil {
+			return err
+		}
+	}
+	if ow.err != nil {
+		return ow.err
+	}
+	if err := ow.Flush(); err != nil {
+		return err
+	}
+
+	mw := &textWriter{Writer: bufio.NewWriter(mtlW)}
+	mw.printf("# Written by ofbx\n")
+	for _, mat := range ow.order {
+		writeMTLMaterial(mw, mat, ow.materials[mat.ID()])
+	}
+	if mw.err != nil {
+		return mw.err
+	}
+	return mw.Flush()
+}
+
+type objWriter struct {
+	textWriter
+	// OBJ indices are global and one based, these count what has been written
+	vertices, uvs, normals int
+	// materials maps material ids to their unique MTL names
+	materials map[uint64]string
+	names     map[string]bool
+	order     []*Material
+}
+
+func (ow *objWriter) writeMesh(mesh *Mesh, opts OBJOptions) error {
+	geom := mesh.Geometry
+	if geom == nil {
+		return nil
+	}
+	transform := IdentityMatrix()
+	if !opts.LocalSpace {
+		transform = mesh.GlobalTransform().Mul(mesh.GeometricTransform())
+	}
+
+	count := 0
+	for _, face := range geom.Faces {
+		count += len(face)
+	}
+	hasUVs := len(geom.UVs[0]) == count
+	hasNormals := len(geom.Normals) == count
+
+	ow.printf("o %s\n", BaseName(mesh.Name()))
+	for _, v := range geom.Vertices {
+		v = transform.TransformPoint(v)
+		ow.printf("v %s %s %s\n", objFloat(v.X()), objFloat(v.Y()), objFloat(v.Z()))
+	}
+	if hasUVs {
+		for _, uv := range geom.UVs[0] {
+			ow.printf("vt %s %s\n", objFloat(uv.X()), objFloat(uv.Y()))
+		}
+	}
+	if hasNormals {
+		// Normals stay perpendicular to surfaces under the inverse transpose
+		normalTransform, _ := transform.Inverse()
+		normalTransform = normalTransform.Transpose()
+		for _, n := range geom.Normals {
+			n = normalTransform.TransformDirection(n).Normalize()
+			ow.printf("vn %s %s %s\n", objFloat(n.X()), objFloat(n.Y()), objFloat(n.Z()))
+		}
+	}
+
+	lastMaterial := -1
+	pv := 0
+	for fi, face := range geom.Faces {
+		// Faces without a material index use the mesh's first material
+		material := 0
+		if fi < len(geom.Materials) {
+			material = geom.Materials[fi]
+		}
+		if material != lastMaterial {
+			lastMaterial = material
+			if lastMaterial >= 0 && lastMaterial < len(mesh.Materials) && mesh.Materials[lastMaterial] != nil {
+				ow.printf("usemtl %s\n", ow.materialName(mesh.Materials[lastMaterial]))
+			}
+		}
+		ow.printf("f")
+		for k, ci := range face {
+			if ci < 0 || ci >= len(geom.Vertices) {
+				return errors.New("Face index out of range in mesh " + mesh.Name())
+			}
+			ref := strconv.Itoa(ow.vertices + ci + 1)
+			if hasUVs || hasNormals {
+				ref += "/"
+				if hasUVs {
+					ref += strconv.Itoa(ow.uvs + pv + k + 1)
+				}
+				if hasNormals {
+					ref += "/" + strconv.Itoa(ow.normals+pv+k+1)
+				}
+			}
+			ow.printf(" %s", ref)
+		}
+		ow.printf("\n")
+		pv += len(face)
+	}
+
+	ow.vertices += len(geom.Vertices)
+	if hasUVs {
+		ow.uvs += count
+	}
+	if hasNormals {
+		ow.normals += count
+	}
+	return nil
+}
+
+// materialName returns the name a material is written to the MTL file with,
+// made unique by appending its id if another material already has its name
+func (ow *objWriter) materialName(mat *Material) string {
+	if name, ok := ow.materials[mat.ID()]; ok {
+		return name
+	}
+	name := strings.Replace(BaseName(mat.Name()), " ", "_", -1)
+	if name == "" || ow.names[name] {
+		name += fmt.Sprintf("_%d", mat.ID())
+	}
+	ow.names[name] = true
+	ow.materials[mat.ID()] = name
+	ow.order = append(ow.order, mat)
+	return name
+}
+
+func writeMTLMaterial(mw *textWriter, mat *Material, name string) {
+	mw.printf("\nnewmtl %s\n", name)
+	mw.printf("Kd %s\n", mtlColor(mat.DiffuseColor))
+	mw.printf("Ks %s\n", mtlColor(mat.SpecularColor))
+	mw.printf("Ns %s\n", objFloat(mat.ShininessExponent))
+	mw.printf("Tf %s\n", mtlColor(mat.TransparentColor))
+	bump := mat.Textures[NORMAL]
+	if bump == nil {
+		bump = mat.Textures[BUMP]
+	}
+	maps := []struct {
+		statement string
+		texture   *Texture
+	}{
+		{"map_Ka", mat.Textures[AMBIENT]},
+		{"map_Kd", mat.Textures[DIFFUSE]},
+		{"map_Ks", mat.Textures[SPECULAR]},
+		{"map_Ke", mat.Textures[EMISSIVE]},
+		{"map_Ns", mat.Textures[SHININESS]},
+		{"map_d", mat.Textures[TRANSPARENCY]},
+		{"map_Bump", bump},
+		{"disp", mat.Textures[DISPLACEMENT]},
+		{"refl", mat.Textures[REFLECTION]},
+	}
+	for _, m := range maps {
+		if file := textureFileName(m.texture); file != "" {
+			mw.printf("%s %s\n", m.statement, file)
+		}
+	}
+}
+
+// textureFileName returns the texture's path with forward slashes, preferring the relative path
+func textureFileName(t *Texture) string {
+	if t == nil {
+		return ""
+	}
+	file := t.RelativeFilename
+	if file == "" {
+		file = t.Filename
+	}
+	return strings.Replace(file, "\\", "/", -1)
+}
+
+func mtlColor(c Color) string {
+	return objFloat(float64(c.R)) + " " + objFloat(float64(c.G)) + " " + objFloat(float64(c.B))
+}
+
+// objFloat formats values with float32 precision, which is what OBJ readers load them as
+func objFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 32)
+}
@@ -0,0 +1,94 @@
+package ofbx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOBJ(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	obj := &bytes.Buffer{}
+	mtl := &bytes.Buffer{}
+	require.Nil(t, WriteOBJ(obj, mtl, scene, OBJOptions{MTLFileName: "cube.mtl", LocalSpace: true}))
+
+	counts := map[string]int{}
+	var faces []string
+	for _, line := range strings.Split(obj.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		counts[fields[0]]++
+		if fields[0] == "f" {
+			faces = append(faces, line)
+		}
+	}
+	require.Equal(t, 8, counts["v"])
+	require.Equal(t, 24, counts["vt"])
+	require.Equal(t, 24, counts["vn"])
+	require.Equal(t, 6, counts["f"])
+	require.Equal(t, 1, counts["usemtl"])
+	require.Contains(t, obj.String(), "mtllib cube.mtl\no Cube\nv -1 -1 -1\n")
+	// Quads are kept as quads
+	require.Equal(t, "f 1/1/1 4/2/2 3/3/3 2/4/4", faces[0])
+
+	require.Contains(t, mtl.String(), "newmtl Red\nKd 0.8 0.2 0.2\n")
+	require.Contains(t, mtl.String(), "Ns 25.5\n")
+	require.Contains(t, mtl.String(), "map_Kd textures/diffuse.png\n")
+}
+
+func TestWriteOBJWorldSpace(t *testing.T) {
+	obj := &bytes.Buffer{}
+	require.Nil(t, WriteOBJ(obj, &bytes.Buffer{}, loadTestScene(t, "testdata/cube.fbx"), OBJOptions{}))
+	require.Contains(t, obj.String(), "mtllib scene.mtl\n")
+	// The cube's translation is applied
+	require.Contains(t, obj.String(), "\nv 0.5 -3 -0.7\n")
+}
+
+const scaledOBJScene = `; FBX 7.4.0 project file
+Objects:  {
+	Geometry: 200, "Geometry::Slope", "Mesh" {
+		Vertices: *9 {
+			a: 1,0,0,0,1,0,0,0,1
+		} 
+		PolygonVertexIndex: *3 {
+			a: 0,1,-3
+		} 
+		LayerElementNormal: 0 {
+			MappingInformationType: "ByPolygonVertex"
+			ReferenceInformationType: "Direct"
+			Normals: *9 {
+				a: 0.5773502691896258,0.5773502691896258,0.5773502691896258,0.5773502691896258,0.5773502691896258,0.5773502691896258,0.5773502691896258,0.5773502691896258,0.5773502691896258
+			} 
+		}
+	}
+	Model: 100, "Model::Slope", "Mesh" {
+		Properties70:  {
+			P: "Lcl Scaling", "Lcl Scaling", "", "A",2,1,1
+		}
+	}
+	Material: 600, "Material::Grass", "" {
+		ShadingModel: "phong"
+	}
+}
+Connections:  {
+	C: "OO",100,0
+	C: "OO",200,100
+	C: "OO",600,100
+}
+`
+
+func TestWriteOBJScaled(t *testing.T) {
+	scene, err := Load(strings.NewReader(scaledOBJScene))
+	require.Nil(t, err)
+	obj := &bytes.Buffer{}
+	require.Nil(t, WriteOBJ(obj, &bytes.Buffer{}, scene, OBJOptions{}))
+	require.Contains(t, obj.String(), "\nv 2 0 0\n")
+	// Normals stay perpendicular to the stretched face
+	require.Contains(t, obj.String(), "\nvn 0.33333334 0.6666667 0.6666667\n")
+	// Without per face material indices the mesh's first material is used
+	require.Contains(t, obj.String(), "\nusemtl Grass\nf 1//1 2//2 3//3\n")
+}
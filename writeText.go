@@ -0,0 +1,207 @@
+package ofbx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EncodeText writes an element tree in Autodesk's ASCII FBX syntax. A root element
+// with no ID, like those built by Load, has its children written as the top level elements.
+func EncodeText(w io.Writer, root *Element) error {
+	tw := &textWriter{Writer: bufio.NewWriter(w)}
+
+	tw.printf("; FBX %s project file\n", textVersion(root))
+	tw.printf("; ----------------------------------------------------\n")
+
+	elems := []*Element{root}
+	if root.ID == nil {
+		elems = root.Children
+	}
+	for _, e := range elems {
+		tw.printf("\n")
+		tw.writeElement(e, "")
+	}
+	if tw.err != nil {
+		return tw.err
+	}
+	return tw.Flush()
+}
+
+// textVersion formats FBXHeaderExtension's FBXVersion, e.g. 7400, as 7.4.0
+func textVersion(root *Element) string {
+	version := 7400
+	for _, header := range findChildren(root, "FBXHeaderExtension") {
+		if header.ID.String() != "FBXHeaderExtension" {
+			continue
+		}
+		if prop := findSingleChildProperty(header, "FBXVersion"); prop != nil {
+			if v, err := strconv.Atoi(prop.stringValue()); err == nil {
+				version = v
+			}
+		}
+		break
+	}
+	return fmt.Sprintf("%d.%d.%d", version/1000, version%1000/100, version%100)
+}
+
+type textWriter struct {
+	*bufio.Writer
+	err error
+	// attrFlags are the KeyAttrFlags of the curve being written
+	attrFlags []int64
+}
+
+func (tw *textWriter) printf(format string, args ...interface{}) {
+	if tw.err != nil {
+		return
+	}
+	_, tw.err = fmt.Fprintf(tw, format, args...)
+}
+
+func (tw *textWriter) writeElement(e *Element, indent string) {
+	id := ""
+	if e.ID != nil {
+		id = e.ID.String()
+	}
+	tw.printf("%s%s: ", indent, id)
+	for i, p := range e.Properties {
+		if i != 0 {
+			tw.printf(", ")
+		}
+		if id == "KeyAttrDataFloat" && p.Type == ArrayFLOAT {
+			tw.writeKeyAttrData(p, indent)
+			continue
+		}
+		tw.writeProperty(p, indent)
+	}
+	// Mirror the binary writer, which writes a null record for empty elements
+	if len(e.Children) == 0 && len(e.Properties) != 0 {
+		tw.printf("\n")
+		return
+	}
+	tw.printf(" {\n")
+	if id == "AnimationCurve" {
+		tw.attrFlags = nil
+		if attrFlags := findSingleChildProperty(e, "KeyAttrFlags"); attrFlags != nil {
+			tw.attrFlags, _ = attrFlags.getValuesInt64()
+		}
+	}
+	for _, c := range e.Children {
+		tw.writeElement(c, indent+"\t")
+	}
+	tw.printf("%s}\n", indent)
+}
+
+func (tw *textWriter) writeProperty(p *Property, indent string) {
+	if p.Type.IsArray() {
+		tw.writeArray(p, indent)
+		return
+	}
+	if p.value.isText && p.Type != STRING {
+		// Values read from ASCII files are still in their literal form
+		tw.printf("%s", p.value.String())
+		return
+	}
+	data := p.value.bytes()
+	if size := p.Type.Size(); size != 0 && len(data) < size {
+		tw.err = errors.New("Invalid length for property type " + string(p.Type))
+		return
+	}
+	switch p.Type {
+	case STRING:
+		s := p.value.String()
+		if !p.value.isText {
+			s = textObjectName(s)
+		}
+		tw.printf("\"%s\"", strings.Replace(s, "\"", "&quot;", -1))
+	case RAWSTRING:
+		if len(data) >= 4 {
+			data = data[4:]
+		}
+		tw.printf("\"%s\"", base64.StdEncoding.EncodeToString(data))
+	case BOOL:
+		// Chars like Shading: T are written as letters, anything else as a number
+		if len(data) == 1 && data[0] >= 'A' && data[0] <= 'Z' {
+			tw.printf("%c", data[0])
+		} else if len(data) == 1 {
+			tw.printf("%d", data[0])
+		}
+	case INT16:
+		tw.printf("%d", int16(binary.LittleEndian.Uint16(data)))
+	case INTEGER:
+		tw.printf("%d", int32(binary.LittleEndian.Uint32(data)))
+	case LONG:
+		tw.printf("%d", int64(binary.LittleEndian.Uint64(data)))
+	case FLOAT:
+		f := math.Float32frombits(binary.LittleEndian.Uint32(data))
+		tw.printf("%s", strconv.FormatFloat(float64(f), 'g', -1, 32))
+	case DOUBLE:
+		f := math.Float64frombits(binary.LittleEndian.Uint64(data))
+		tw.printf("%s", strconv.FormatFloat(f, 'g', -1, 64))
+	default:
+		tw.printf("\"%s\"", p.stringValue())
+	}
+}
+
+func (tw *textWriter) writeArray(p *Property, indent string) {
+	data, err := arrayBytes(p)
+	if err != nil {
+		tw.err = err
+		return
+	}
+	vals := make([]string, 0, p.Count)
+	r := bytes.NewReader(data)
+	switch p.Type {
+	case ArrayDOUBLE, ArrayFLOAT:
+		for _, f := range parseArrayRawFloat64End(r, p.Count, p.Type.Size()) {
+			vals = append(vals, strconv.FormatFloat(f, 'g', -1, p.Type.Size()*8))
+		}
+	case ArrayINT, ArrayLONG:
+		for _, i := range parseArrayRawInt64End(r, p.Count, p.Type.Size()) {
+			vals = append(vals, strconv.FormatInt(i, 10))
+		}
+	case ArrayBOOL, ArrayBYTE:
+		for _, b := range data {
+			vals = append(vals, strconv.Itoa(int(b)))
+		}
+	}
+	tw.printf("*%d {\n%s\ta: %s\n%s} ", len(vals), indent, strings.Join(vals, ","), indent)
+}
+
+// writeKeyAttrData writes a curve's KeyAttrDataFloat with its packed pairs as
+// the integers their bits hold, as Autodesk's ASCII files do
+func (tw *textWriter) writeKeyAttrData(p *Property, indent string) {
+	f32s, err := p.getValuesF32()
+	if err != nil {
+		tw.err = err
+		return
+	}
+	vals := make([]string, len(f32s))
+	for i, f := range f32s {
+		if keyAttrPacked(i, tw.attrFlags) {
+			vals[i] = strconv.FormatInt(int64(int32(math.Float32bits(f))), 10)
+		} else {
+			vals[i] = strconv.FormatFloat(float64(f), 'g', -1, 32)
+		}
+	}
+	tw.printf("*%d {\n%s\ta: %s\n%s} ", len(vals), indent, strings.Join(vals, ","), indent)
+}
+
+// textObjectName converts a binary style object name, Cube\x00\x01Model,
+// into the ASCII style, Model::Cube
+func textObjectName(s string) string {
+	idx := strings.Index(s, "\x00\x01")
+	if idx == -1 {
+		return s
+	}
+	return s[idx+2:] + "::" + s[:idx]
+}
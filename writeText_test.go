@@ -0,0 +1,42 @@
+package ofbx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeTextRoundTrip(t *testing.T) {
+	text := loadTestScene(t, "testdata/cube.fbx")
+	binBuff := &bytes.Buffer{}
+	require.Nil(t, Save(binBuff, text, WriteOptions{Compression: CompressArrays}))
+	bin, err := Load(binBuff)
+	require.Nil(t, err)
+
+	for _, scene := range []*Scene{text, bin} {
+		buff := &bytes.Buffer{}
+		require.Nil(t, EncodeText(buff, scene.RootElement))
+		written := buff.String()
+		require.True(t, strings.HasPrefix(written, "; FBX 7.4.0 project file\n"))
+		require.Contains(t, written, `Model: 100, "Model::Cube", "Mesh" {`)
+		require.Contains(t, written, "\t\tPolygonVertexIndex: *24 {\n\t\t\ta: 0,3,2,-2,4,5,6,-8,")
+		require.Contains(t, written, "\t\tKeyAttrDataFloat: *4 {\n\t\t\ta: 0,0,255790911,0\n")
+
+		reread, err := Load(strings.NewReader(written))
+		require.Nil(t, err)
+		require.Equal(t, "Model::Cube", reread.Meshes[0].Name())
+		require.Equal(t, getLocalTranslation(text.Meshes[0]), getLocalTranslation(reread.Meshes[0]))
+		require.Equal(t, text.Meshes[0].Geometry.Vertices, reread.Meshes[0].Geometry.Vertices)
+		require.Equal(t, text.Meshes[0].Geometry.Faces, reread.Meshes[0].Geometry.Faces)
+		require.Equal(t, text.Meshes[0].Materials[0].DiffuseColor, reread.Meshes[0].Materials[0].DiffuseColor)
+		require.Equal(t, text.AnimationStacks[0].Layers[0].CurveNodes[0].Curves[2].Curve.Values,
+			reread.AnimationStacks[0].Layers[0].CurveNodes[0].Curves[2].Curve.Values)
+
+		// Encoding what we read back should be lossless
+		buff2 := &bytes.Buffer{}
+		require.Nil(t, EncodeText(buff2, reread.RootElement))
+		require.Equal(t, written, buff2.String())
+	}
+}
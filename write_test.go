@@ -0,0 +1,111 @@
+package ofbx
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestScene(t *testing.T, path string) *Scene {
+	f, err := os.Open(path)
+	require.Nil(t, err)
+	defer f.Close()
+	scene, err := Load(f)
+	require.Nil(t, err)
+	return scene
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	testCases := []WriteOptions{
+		{},
+		{Version: 7500, Compression: CompressArrays},
+		{Version: 7400, Compression: NoCompression},
+	}
+	for _, opts := range testCases {
+		text := loadTestScene(t, "testdata/cube.fbx")
+		buff := &bytes.Buffer{}
+		require.Nil(t, Save(buff, text, opts))
+		written := buff.Bytes()
+		require.True(t, isBinary(bytes.NewReader(written)))
+
+		scene, err := Load(bytes.NewReader(written))
+		require.Nil(t, err, "%+v", opts)
+		require.Len(t, scene.Meshes, 1)
+		mesh := scene.Meshes[0]
+		require.Equal(t, "Cube\x00\x01Model", mesh.Name())
+		require.Equal(t, getLocalTranslation(text.Meshes[0]), getLocalTranslation(mesh))
+		require.Equal(t, text.Meshes[0].Geometry.Vertices, mesh.Geometry.Vertices)
+		require.Equal(t, text.Meshes[0].Geometry.Faces, mesh.Geometry.Faces)
+		require.Equal(t, text.Meshes[0].Materials[0].DiffuseColor, mesh.Materials[0].DiffuseColor)
+		require.Equal(t, text.Meshes[0].Geometry.Skin.Clusters[1].TransformLink, mesh.Geometry.Skin.Clusters[1].TransformLink)
+		require.Equal(t, text.AnimationStacks[0].Layers[0].CurveNodes[0].Curves[2].Curve.Values,
+			scene.AnimationStacks[0].Layers[0].CurveNodes[0].Curves[2].Curve.Values)
+
+		// Writing what we read back should be lossless
+		buff2 := &bytes.Buffer{}
+		require.Nil(t, Save(buff2, scene, opts))
+		require.Equal(t, written, buff2.Bytes())
+	}
+}
+
+func TestWriteElementRename(t *testing.T) {
+	scene := loadTestScene(t, "testdata/cube.fbx")
+	bone := scene.Meshes[0].Geometry.Skin.Clusters[1].Link
+	bone.Element().Properties[1] = NewStringProperty("Spine\x00\x01Model")
+
+	buff := &bytes.Buffer{}
+	require.Nil(t, WriteElement(buff, scene.RootElement, WriteOptions{}))
+	scene, err := Load(buff)
+	require.Nil(t, err)
+	require.Equal(t, "Spine\x00\x01Model", scene.Meshes[0].Geometry.Skin.Clusters[1].Link.Name())
+}
+
+const objectNameScene = `; FBX 7.4.0 project file
+Objects:  {
+	Model: 100, "Model::Crate", "Null" {
+		Properties70:  {
+			P: "Path", "KString", "", "U", "Props::Crate::Lid"
+		}
+	}
+}
+Connections:  {
+	C: "OO",100,0
+}
+`
+
+func TestSaveObjectNames(t *testing.T) {
+	text, err := Load(strings.NewReader(objectNameScene))
+	require.Nil(t, err)
+	buff := &bytes.Buffer{}
+	require.Nil(t, Save(buff, text, WriteOptions{}))
+
+	scene, err := Load(buff)
+	require.Nil(t, err)
+	crate := scene.ObjectMap[100]
+	require.Equal(t, "Crate\x00\x01Model", crate.Name())
+	path, ok := crate.Property("Path")
+	require.True(t, ok)
+	require.Equal(t, "Props::Crate::Lid", path.Value)
+}
+
+func TestSaveTextPropertyTypes(t *testing.T) {
+	// Values read from ASCII files are written with the types Autodesk's binary files use
+	text := loadTestScene(t, "testdata/cube.fbx")
+	buff := &bytes.Buffer{}
+	require.Nil(t, Save(buff, text, WriteOptions{}))
+	scene, err := Load(buff)
+	require.Nil(t, err)
+
+	header := findChildren(scene.RootElement, "FBXHeaderExtension")[0]
+	require.Equal(t, INTEGER, findSingleChildProperty(header, "FBXVersion").Type)
+	geometry := scene.Meshes[0].Geometry.Element()
+	require.Equal(t, ArrayDOUBLE, findSingleChildProperty(geometry, "Vertices").Type)
+	require.Equal(t, ArrayINT, findSingleChildProperty(geometry, "PolygonVertexIndex").Type)
+	curve := scene.AnimationStacks[0].Layers[0].CurveNodes[0].Curves[2].Curve.Element()
+	require.Equal(t, ArrayLONG, findSingleChildProperty(curve, "KeyTime").Type)
+	require.Equal(t, ArrayFLOAT, findSingleChildProperty(curve, "KeyValueFloat").Type)
+	require.Equal(t, ArrayFLOAT, findSingleChildProperty(curve, "KeyAttrDataFloat").Type)
+}